@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/jfcantu/threatstack-golang/threatstack"
+)
+
+// agentStatuses are the values of the agents API status filter. The API only
+// returns agents with a single status per request.
+var agentStatuses = []string{"online", "offline"}
+
+// agent is an agent as returned by the agents API, which the client library
+// doesn't cover.
+type agent struct {
+	ID             string             `json:"id"`
+	InstanceID     string             `json:"instanceId"`
+	Status         string             `json:"status"`
+	CreatedAt      string             `json:"createdAt"`
+	LastReportedAt string             `json:"lastReportedAt"`
+	Version        string             `json:"version"`
+	Name           string             `json:"name"`
+	Description    string             `json:"description"`
+	Hostname       string             `json:"hostname"`
+	IPAddresses    agentIPAddresses   `json:"ipAddresses"`
+	Tags           []*threatstack.Tag `json:"tags"`
+	AgentType      string             `json:"agentType"`
+	OSVersion      string             `json:"osVersion"`
+	Kernel         string             `json:"kernel"`
+	Rulesets       []string           `json:"rulesets"`
+}
+
+type agentIPAddresses struct {
+	Private   []string `json:"private"`
+	LinkLocal []string `json:"link_local"`
+	Public    []string `json:"public"`
+}
+
+type agentList struct {
+	Agents []*agent `json:"agents"`
+	Token  string   `json:"token"`
+}
+
+// listAgents retrieves every agent with the given status, following
+// pagination tokens. The client library drops query parameters passed as
+// options, so the query is encoded into the path.
+func listAgents(client *threatstack.Client, status string) ([]*agent, error) {
+	var ret []*agent
+
+	query := url.Values{}
+	query.Set("status", status)
+	for {
+		raw, err := client.GetObject("agents?"+query.Encode(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("Error listing %s agents: %s", status, err.Error())
+		}
+
+		page := new(agentList)
+		if err := json.Unmarshal(raw, page); err != nil {
+			return nil, fmt.Errorf("Error parsing agent list: %s", err.Error())
+		}
+
+		ret = append(ret, page.Agents...)
+		if page.Token == "" {
+			return ret, nil
+		}
+		query.Set("token", page.Token)
+	}
+}
+
+// listHostTags returns every distinct tag of every agent, sorted.
+func listHostTags(client *threatstack.Client) ([]*threatstack.Tag, error) {
+	seen := map[string]bool{}
+	var ret []*threatstack.Tag
+
+	for _, status := range agentStatuses {
+		agents, err := listAgents(client, status)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, a := range agents {
+			for _, t := range a.Tags {
+				k := ruleTagKey(t.Source, t.Key, t.Value)
+				if !seen[k] {
+					seen[k] = true
+					ret = append(ret, t)
+				}
+			}
+		}
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		return ruleTagKey(ret[i].Source, ret[i].Key, ret[i].Value) < ruleTagKey(ret[j].Source, ret[j].Key, ret[j].Value)
+	})
+
+	return ret, nil
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestListHostTags(test *testing.T) {
+	client := testAPIClient(test, map[string]string{
+		"/v2/agents?status=online": `{"agents": [
+			{"id": "a1", "tags": [{"source": "ec2", "key": "env", "value": "prod"}]}
+		], "token": "page2"}`,
+		"/v2/agents?status=online&token=page2": `{"agents": [
+			{"id": "a2", "tags": [{"source": "ec2", "key": "env", "value": "prod"}, {"source": "ec2", "key": "app", "value": "web"}]}
+		]}`,
+		"/v2/agents?status=offline": `{"agents": [
+			{"id": "a3", "tags": [{"source": "ec2", "key": "env", "value": "dev"}]}
+		]}`,
+	})
+
+	tags, err := listHostTags(client)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	var got []string
+	for _, v := range tags {
+		got = append(got, formatTag(v))
+	}
+	expected := []string{"ec2:app=web", "ec2:env=dev", "ec2:env=prod"}
+	if !reflect.DeepEqual(got, expected) {
+		test.Errorf("Expected %v, got %v", expected, got)
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"github.com/jfcantu/threatstack-golang/threatstack"
+)
+
+// alertStatuses are the values of the alerts API status filter. The API only
+// returns alerts with a single status per request.
+var alertStatuses = []string{"active", "dismissed"}
+
+// alert is an alert as returned by the alerts API, which the client library
+// doesn't cover.
+type alert struct {
+	ID                string `json:"id"`
+	Title             string `json:"title"`
+	DataSource        string `json:"dataSource"`
+	CreatedAt         string `json:"createdAt"`
+	IsDismissed       bool   `json:"isDismissed"`
+	DismissedAt       string `json:"dismissedAt"`
+	DismissReason     string `json:"dismissReason"`
+	DismissReasonText string `json:"dismissReasonText"`
+	DismissedBy       string `json:"dismissedBy"`
+	Severity          int    `json:"severity"`
+	AgentID           string `json:"agentId"`
+	RulesetID         string `json:"rulesetId"`
+	RuleID            string `json:"ruleId"`
+}
+
+// alertDismissReasons are the reasons the API accepts for dismissing alerts.
+var alertDismissReasons = []string{
+	"BUSINESS_OP",
+	"COMPANY_POLICY",
+	"MAINTENANCE",
+	"NONE",
+	"OTHER",
+}
+
+// alertDismissBatchSize is the most alerts the API dismisses per request.
+const alertDismissBatchSize = 512
+
+type alertDismissal struct {
+	IDs               []string `json:"ids"`
+	DismissReason     string   `json:"dismissReason"`
+	DismissReasonText string   `json:"dismissReasonText,omitempty"`
+}
+
+type alertList struct {
+	Alerts []*alert `json:"alerts"`
+	Token  string   `json:"token"`
+}
+
+// alertQuery selects alerts. Empty fields match everything. From and Until
+// are RFC 3339 timestamps.
+type alertQuery struct {
+	Status    string
+	RuleID    string
+	RulesetID string
+	Severity  int
+	From      string
+	Until     string
+}
+
+// listAlerts retrieves every alert matching query, following pagination
+// tokens, most recent first. The API doesn't filter on ruleset, so that's
+// done here.
+func listAlerts(client *threatstack.Client, query *alertQuery) ([]*alert, error) {
+	statuses := alertStatuses
+	if query.Status != "" {
+		statuses = []string{query.Status}
+	}
+
+	var ret []*alert
+	for _, status := range statuses {
+		params := url.Values{}
+		params.Set("status", status)
+		if query.RuleID != "" {
+			params.Set("ruleId", query.RuleID)
+		}
+		if query.Severity != 0 {
+			params.Set("severity", strconv.Itoa(query.Severity))
+		}
+		if query.From != "" {
+			params.Set("from", query.From)
+		}
+		if query.Until != "" {
+			params.Set("until", query.Until)
+		}
+
+		for {
+			raw, err := client.GetObject("alerts?"+params.Encode(), nil)
+			if err != nil {
+				return nil, fmt.Errorf("Error listing %s alerts: %s", status, err.Error())
+			}
+
+			page := new(alertList)
+			if err := json.Unmarshal(raw, page); err != nil {
+				return nil, fmt.Errorf("Error parsing alert list: %s", err.Error())
+			}
+
+			for _, v := range page.Alerts {
+				if query.RulesetID == "" || v.RulesetID == query.RulesetID {
+					ret = append(ret, v)
+				}
+			}
+
+			if page.Token == "" {
+				break
+			}
+			params.Set("token", page.Token)
+		}
+	}
+
+	// Timestamps are RFC 3339 in UTC, so they sort as strings.
+	sort.SliceStable(ret, func(i, j int) bool {
+		if ret[i].CreatedAt != ret[j].CreatedAt {
+			return ret[i].CreatedAt > ret[j].CreatedAt
+		}
+		return ret[i].ID < ret[j].ID
+	})
+
+	return ret, nil
+}
+
+// dismissAlerts dismisses the given alerts, in batches. It returns the IDs
+// that were dismissed before any error.
+func dismissAlerts(client *threatstack.Client, ids []string, reason, text string) ([]string, error) {
+	var done []string
+	for start := 0; start < len(ids); start += alertDismissBatchSize {
+		end := start + alertDismissBatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		body := &alertDismissal{
+			IDs:               ids[start:end],
+			DismissReason:     reason,
+			DismissReasonText: text,
+		}
+		if _, err := client.CreateObject("alerts/dismiss", nil, body); err != nil {
+			return done, fmt.Errorf("Error dismissing alerts: %s", err.Error())
+		}
+
+		done = append(done, ids[start:end]...)
+	}
+
+	return done, nil
+}
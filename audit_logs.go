@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/jfcantu/threatstack-golang/threatstack"
+)
+
+// auditLog is an entry in the organization's audit log, as returned by the
+// audit logs API, which the client library doesn't cover.
+type auditLog struct {
+	ID          string `json:"id"`
+	CreatedAt   string `json:"createdAt"`
+	UserID      string `json:"userId"`
+	UserEmail   string `json:"userEmail"`
+	Action      string `json:"action"`
+	ObjectType  string `json:"objectType"`
+	ObjectID    string `json:"objectId"`
+	ObjectName  string `json:"objectName"`
+	Description string `json:"description"`
+	SourceIP    string `json:"sourceIp"`
+}
+
+type auditLogList struct {
+	AuditLogs []*auditLog `json:"auditLogs"`
+	Token     string      `json:"token"`
+}
+
+// auditLogQuery selects audit log entries. Empty fields match everything.
+// From and Until are RFC 3339 timestamps.
+type auditLogQuery struct {
+	From     string
+	Until    string
+	UserID   string
+	ObjectID string
+}
+
+// listAuditLogs retrieves every audit log entry matching query, following
+// pagination tokens, most recent first.
+func listAuditLogs(client *threatstack.Client, query *auditLogQuery) ([]*auditLog, error) {
+	params := url.Values{}
+	if query.From != "" {
+		params.Set("from", query.From)
+	}
+	if query.Until != "" {
+		params.Set("until", query.Until)
+	}
+	if query.UserID != "" {
+		params.Set("userId", query.UserID)
+	}
+	if query.ObjectID != "" {
+		params.Set("objectId", query.ObjectID)
+	}
+
+	var ret []*auditLog
+	for {
+		path := "auditlogs"
+		if len(params) > 0 {
+			path += "?" + params.Encode()
+		}
+
+		raw, err := client.GetObject(path, nil)
+		if err != nil {
+			return nil, fmt.Errorf("Error listing audit logs: %s", err.Error())
+		}
+
+		page := new(auditLogList)
+		if err := json.Unmarshal(raw, page); err != nil {
+			return nil, fmt.Errorf("Error parsing audit log list: %s", err.Error())
+		}
+
+		ret = append(ret, page.AuditLogs...)
+		if page.Token == "" {
+			break
+		}
+		params.Set("token", page.Token)
+	}
+
+	// Timestamps are RFC 3339 in UTC, so they sort as strings.
+	sort.SliceStable(ret, func(i, j int) bool {
+		if ret[i].CreatedAt != ret[j].CreatedAt {
+			return ret[i].CreatedAt > ret[j].CreatedAt
+		}
+		return ret[i].ID < ret[j].ID
+	})
+
+	return ret, nil
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/jfcantu/threatstack-golang/threatstack"
+)
+
+// command is a mode of the provider binary that is run from the command line
+// instead of being served to Terraform as a plugin.
+type command struct {
+	Synopsis string
+	Run      func(args []string) int
+}
+
+var commands = map[string]*command{
+	"backup": &command{
+		Synopsis: "Write every ruleset and rule to a JSON snapshot",
+		Run:      runBackupCommand,
+	},
+	"drift": &command{
+		Synopsis: "Report rulesets and rules that differ from Terraform state",
+		Run:      runDriftCommand,
+	},
+	"falco": &command{
+		Synopsis: "Convert Falco rules into Terraform configuration",
+		Run:      runFalcoCommand,
+	},
+	"generate": &command{
+		Synopsis: "Generate Terraform configuration from existing rulesets and rules",
+		Run:      runGenerateCommand,
+	},
+	"lint": &command{
+		Synopsis: "Report noisy or dangerous rule configurations",
+		Run:      runLintCommand,
+	},
+	"restore": &command{
+		Synopsis: "Recreate rulesets and rules from a JSON snapshot",
+		Run:      runRestoreCommand,
+	},
+}
+
+// runCommand runs the command named by the first argument and returns the
+// process exit code.
+func runCommand(args []string) int {
+	cmd, ok := commands[args[0]]
+	if !ok {
+		printCommandUsage(os.Stderr)
+		return 1
+	}
+
+	return cmd.Run(args[1:])
+}
+
+func printCommandUsage(w io.Writer) {
+	var names []string
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintf(w, "Usage: %s <command> [options]\n\nAvailable commands:\n", filepath.Base(os.Args[0]))
+	for _, name := range names {
+		fmt.Fprintf(w, "    %-12s %s\n", name, commands[name].Synopsis)
+	}
+}
+
+// commandConfig reads the client configuration from the same credential
+// process, environment variables and shared credentials file as the provider
+// configuration. It also sets up the proxy and TLS settings from the
+// environment.
+func commandConfig() (*Config, error) {
+	transport, err := envTransportConfig()
+	if err != nil {
+		return nil, err
+	}
+	if err := transport.Install(); err != nil {
+		return nil, err
+	}
+
+	// As in the provider, later sources are only read if the earlier ones
+	// set nothing.
+	if command := os.Getenv("THREATSTACK_CREDENTIAL_PROCESS"); command != "" {
+		source, err := (&credentialProcess{Command: command}).Source()
+		if err != nil {
+			return nil, err
+		}
+		return resolveCredentials([]*credentialSource{source})
+	}
+
+	env := envCredentialSource()
+	if env.Config != (Config{}) {
+		return resolveCredentials([]*credentialSource{env})
+	}
+
+	profile, err := profileCredentialSource(os.Getenv("THREATSTACK_SHARED_CREDENTIALS_FILE"), os.Getenv("THREATSTACK_PROFILE"))
+	if err != nil {
+		return nil, err
+	}
+
+	return resolveCredentials([]*credentialSource{env, profile})
+}
+
+func commandClient() (*threatstack.Client, error) {
+	config, err := commandConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return config.Client()
+}
+
+// rulesetRules is a ruleset along with the full definitions of its rules.
+type rulesetRules struct {
+	Ruleset *threatstack.Ruleset
+	Rules   []threatstack.Rule
+}
+
+// getRulesetRules retrieves a ruleset and all of its rules.
+func getRulesetRules(client *threatstack.Client, id string) (*rulesetRules, error) {
+	ruleset, err := client.Rulesets.Get(id)
+	if err != nil {
+		return nil, fmt.Errorf("Error retrieving ruleset %s: %s", id, err.Error())
+	}
+
+	entry := &rulesetRules{Ruleset: ruleset}
+	for _, ruleID := range ruleset.RuleIDs {
+		rule, err := client.Rules.Get(ruleset.ID, ruleID)
+		if err != nil {
+			return nil, fmt.Errorf("Error retrieving rule %s from ruleset %s: %s", ruleID, ruleset.ID, err.Error())
+		}
+
+		entry.Rules = append(entry.Rules, *rule)
+	}
+
+	return entry, nil
+}
+
+// listRulesetRules retrieves every ruleset in the organization and all of
+// their rules.
+func listRulesetRules(client *threatstack.Client) ([]*rulesetRules, error) {
+	rulesets, err := client.Rulesets.List()
+	if err != nil {
+		return nil, fmt.Errorf("Error listing rulesets: %s", err.Error())
+	}
+
+	var ret []*rulesetRules
+	for _, v := range rulesets {
+		// Rulesets.List doesn't populate RuleIDs, so each ruleset has to be
+		// retrieved individually.
+		entry, err := getRulesetRules(client, v.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		ret = append(ret, entry)
+	}
+
+	return ret, nil
+}
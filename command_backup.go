@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+func runBackupCommand(args []string) int {
+	flags := flag.NewFlagSet("backup", flag.ContinueOnError)
+	outFile := flags.String("out", "-", "File to write the snapshot to, or - for stdout")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	config, err := commandConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return 1
+	}
+
+	client, err := config.Client()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return 1
+	}
+
+	rulesets, err := listRulesetRules(client)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return 1
+	}
+
+	snap, err := newSnapshot(config.OrganizationID, rulesets)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return 1
+	}
+
+	if err := writeCommandJSON(*outFile, snap); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func runRestoreCommand(args []string) int {
+	flags := flag.NewFlagSet("restore", flag.ContinueOnError)
+	inFile := flags.String("in", "-", "File to read the snapshot from, or - for stdin")
+	mapFile := flags.String("map", "-", "File to write the mapping of old to new IDs to, or - for stdout")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	var in io.Reader = os.Stdin
+	if *inFile != "-" {
+		f, err := os.Open(*inFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			return 1
+		}
+		defer f.Close()
+		in = f
+	}
+
+	snap, err := readSnapshot(in)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return 1
+	}
+
+	client, err := commandClient()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return 1
+	}
+
+	// The mapping is written even if the restore fails partway, so that
+	// whatever was created can be found and cleaned up.
+	mapping, restoreErr := restoreSnapshot(client, snap)
+	if err := writeCommandJSON(*mapFile, mapping); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return 1
+	}
+
+	if restoreErr != nil {
+		fmt.Fprintln(os.Stderr, restoreErr.Error())
+		return 1
+	}
+
+	return 0
+}
+
+// writeCommandJSON writes indented JSON to a file, or to stdout if the file
+// name is "-".
+func writeCommandJSON(name string, v interface{}) error {
+	var out io.Writer = os.Stdout
+	if name != "-" {
+		f, err := os.Create(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -0,0 +1,470 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/jfcantu/threatstack-golang/threatstack"
+)
+
+// stateFile is the subset of the Terraform (0.12+) state format needed to
+// find Threat Stack resources.
+type stateFile struct {
+	Version   int              `json:"version"`
+	Resources []*stateResource `json:"resources"`
+}
+
+type stateResource struct {
+	Mode      string           `json:"mode"`
+	Module    string           `json:"module,omitempty"`
+	Type      string           `json:"type"`
+	Name      string           `json:"name"`
+	Instances []*stateInstance `json:"instances"`
+}
+
+type stateInstance struct {
+	IndexKey   interface{}            `json:"index_key,omitempty"`
+	Attributes map[string]interface{} `json:"attributes"`
+}
+
+// managedObject is a ruleset or rule found in a state file.
+type managedObject struct {
+	Address        string
+	Type           string
+	ID             string
+	RulesetID      string
+	OrganizationID string
+	Attributes     map[string]interface{}
+}
+
+type driftReport struct {
+	Unmanaged []*driftEntry `json:"unmanaged"`
+	Missing   []*driftEntry `json:"missing"`
+	Drifted   []*driftEntry `json:"drifted"`
+}
+
+type driftEntry struct {
+	Kind      string   `json:"kind"`
+	ID        string   `json:"id"`
+	RulesetID string   `json:"ruleset_id,omitempty"`
+	Name      string   `json:"name,omitempty"`
+	Address   string   `json:"address,omitempty"`
+	Fields    []string `json:"fields,omitempty"`
+}
+
+func runDriftCommand(args []string) int {
+	flags := flag.NewFlagSet("drift", flag.ContinueOnError)
+	jsonOutput := flags.Bool("json", false, "Write the report as JSON")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if flags.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "At least one state file must be given")
+		return 1
+	}
+
+	var managed []*managedObject
+	for _, name := range flags.Args() {
+		objects, err := readStateFile(name)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			return 1
+		}
+		managed = append(managed, objects...)
+	}
+
+	config, err := commandConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return 1
+	}
+
+	client, err := config.Client()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return 1
+	}
+
+	managed = organizationObjects(managed, config.OrganizationID)
+
+	live, err := listRulesetRules(client)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return 1
+	}
+
+	report := newDriftReport(managed, live)
+
+	if *jsonOutput {
+		if err := writeCommandJSON("-", report); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			return 1
+		}
+	} else {
+		report.writeText(os.Stdout)
+	}
+
+	if len(report.Unmanaged) > 0 || len(report.Missing) > 0 || len(report.Drifted) > 0 {
+		return 2
+	}
+	return 0
+}
+
+func readStateFile(name string) ([]*managedObject, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	objects, err := parseState(f)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading state file %s: %s", name, err.Error())
+	}
+
+	return objects, nil
+}
+
+func parseState(r io.Reader) ([]*managedObject, error) {
+	state := new(stateFile)
+	if err := json.NewDecoder(r).Decode(state); err != nil {
+		return nil, err
+	}
+
+	if state.Version != 4 {
+		return nil, fmt.Errorf("Unsupported state version %d, only Terraform 0.12+ state is supported", state.Version)
+	}
+
+	var ret []*managedObject
+	for _, res := range state.Resources {
+		if res.Mode != "managed" || !strings.HasPrefix(res.Type, "threatstack_") {
+			continue
+		}
+
+		for _, inst := range res.Instances {
+			address := fmt.Sprintf("%s.%s", res.Type, res.Name)
+			if res.Module != "" {
+				address = fmt.Sprintf("%s.%s", res.Module, address)
+			}
+			if inst.IndexKey != nil {
+				key, _ := json.Marshal(inst.IndexKey)
+				address = fmt.Sprintf("%s[%s]", address, key)
+			}
+
+			obj := &managedObject{
+				Address:        address,
+				Type:           res.Type,
+				ID:             stateString(inst.Attributes, "id"),
+				RulesetID:      stateString(inst.Attributes, "ruleset"),
+				OrganizationID: stateString(inst.Attributes, "organization_id"),
+				Attributes:     inst.Attributes,
+			}
+			ret = append(ret, obj)
+
+			// Copied and replicated rules are created by
+			// threatstack_ruleset_copy and threatstack_ruleset_replica, so
+			// they are managed even though no resource refers to them.
+			if res.Type == "threatstack_ruleset_copy" || res.Type == "threatstack_ruleset_replica" {
+				copies, _ := inst.Attributes["rule_ids"].(map[string]interface{})
+				for _, v := range copies {
+					ret = append(ret, &managedObject{
+						Address:        address,
+						Type:           "threatstack_ruleset_copy_rule",
+						ID:             fmt.Sprint(v),
+						RulesetID:      obj.ID,
+						OrganizationID: obj.OrganizationID,
+					})
+				}
+			}
+		}
+	}
+
+	return ret, nil
+}
+
+// organizationObjects returns the objects managed in the given organization,
+// leaving out resources with a different organization_id.
+func organizationObjects(managed []*managedObject, orgID string) []*managedObject {
+	var ret []*managedObject
+	for _, v := range managed {
+		if v.OrganizationID == "" || v.OrganizationID == orgID {
+			ret = append(ret, v)
+		}
+	}
+	return ret
+}
+
+func newDriftReport(managed []*managedObject, live []*rulesetRules) *driftReport {
+	report := &driftReport{
+		Unmanaged: []*driftEntry{},
+		Missing:   []*driftEntry{},
+		Drifted:   []*driftEntry{},
+	}
+
+	managedRulesets := map[string]*managedObject{}
+	managedRules := map[string]*managedObject{}
+	for _, v := range managed {
+		switch v.Type {
+		case "threatstack_ruleset", "threatstack_ruleset_copy", "threatstack_ruleset_replica":
+			managedRulesets[v.ID] = v
+		case "threatstack_host_rule", "threatstack_file_rule", "threatstack_rule", "threatstack_ruleset_copy_rule":
+			managedRules[v.ID] = v
+		}
+	}
+
+	liveRulesets := map[string]bool{}
+	liveRules := map[string]bool{}
+
+	for _, rs := range live {
+		liveRulesets[rs.Ruleset.ID] = true
+
+		if obj, ok := managedRulesets[rs.Ruleset.ID]; !ok {
+			report.Unmanaged = append(report.Unmanaged, &driftEntry{
+				Kind: "ruleset",
+				ID:   rs.Ruleset.ID,
+				Name: rs.Ruleset.Name,
+			})
+		} else if fields := diffAttributes(obj.Attributes, rulesetAttributes(rs.Ruleset)); len(fields) > 0 {
+			report.Drifted = append(report.Drifted, &driftEntry{
+				Kind:    "ruleset",
+				ID:      rs.Ruleset.ID,
+				Name:    rs.Ruleset.Name,
+				Address: obj.Address,
+				Fields:  fields,
+			})
+		}
+
+		for _, rule := range rs.Rules {
+			id := rule.GetID()
+			liveRules[id] = true
+
+			attrs := liveRuleAttributes(rule)
+			name, _ := attrs["name"].(string)
+
+			obj, ok := managedRules[id]
+			if !ok {
+				report.Unmanaged = append(report.Unmanaged, &driftEntry{
+					Kind:      "rule",
+					ID:        id,
+					RulesetID: rs.Ruleset.ID,
+					Name:      name,
+				})
+				continue
+			}
+
+			// Only the typed rule resources have attributes that can be
+			// compared field by field.
+			if obj.Type != "threatstack_host_rule" && obj.Type != "threatstack_file_rule" {
+				continue
+			}
+
+			if fields := diffAttributes(obj.Attributes, attrs); len(fields) > 0 {
+				report.Drifted = append(report.Drifted, &driftEntry{
+					Kind:      "rule",
+					ID:        id,
+					RulesetID: rs.Ruleset.ID,
+					Name:      name,
+					Address:   obj.Address,
+					Fields:    fields,
+				})
+			}
+		}
+	}
+
+	for _, v := range managed {
+		var kind string
+		var exists bool
+
+		switch v.Type {
+		case "threatstack_ruleset", "threatstack_ruleset_copy", "threatstack_ruleset_replica":
+			kind, exists = "ruleset", liveRulesets[v.ID]
+		case "threatstack_host_rule", "threatstack_file_rule", "threatstack_rule", "threatstack_ruleset_copy_rule":
+			kind, exists = "rule", liveRules[v.ID]
+		default:
+			continue
+		}
+
+		if !exists {
+			report.Missing = append(report.Missing, &driftEntry{
+				Kind:      kind,
+				ID:        v.ID,
+				RulesetID: v.RulesetID,
+				Name:      stateString(v.Attributes, "name"),
+				Address:   v.Address,
+			})
+		}
+	}
+
+	return report
+}
+
+func (report *driftReport) writeText(w io.Writer) {
+	sections := []struct {
+		title   string
+		entries []*driftEntry
+	}{
+		{"Unmanaged (in Threat Stack but not in any state)", report.Unmanaged},
+		{"Missing (in state but not in Threat Stack)", report.Missing},
+		{"Drifted (changed outside of Terraform)", report.Drifted},
+	}
+
+	for _, section := range sections {
+		fmt.Fprintf(w, "%s: %d\n", section.title, len(section.entries))
+		for _, v := range section.entries {
+			fmt.Fprintf(w, "  %s %s", v.Kind, v.ID)
+			if v.Name != "" {
+				fmt.Fprintf(w, " (%s)", v.Name)
+			}
+			if v.RulesetID != "" {
+				fmt.Fprintf(w, " in ruleset %s", v.RulesetID)
+			}
+			if v.Address != "" {
+				fmt.Fprintf(w, " [%s]", v.Address)
+			}
+			if len(v.Fields) > 0 {
+				fmt.Fprintf(w, ": %s", strings.Join(v.Fields, ", "))
+			}
+			fmt.Fprintln(w)
+		}
+	}
+}
+
+func rulesetAttributes(ruleset *threatstack.Ruleset) map[string]interface{} {
+	return map[string]interface{}{
+		"name":        ruleset.Name,
+		"description": ruleset.Description,
+	}
+}
+
+// liveRuleAttributes returns a rule's fields keyed by the attribute names used
+// in state, in the same form as normalizeStateValue produces.
+func liveRuleAttributes(rule threatstack.Rule) map[string]interface{} {
+	attrs := map[string]interface{}{}
+
+	var tags *threatstack.TagSet
+	switch r := rule.(type) {
+	case *threatstack.HostRule:
+		attrs["name"] = r.Name
+		attrs["title"] = r.Title
+		attrs["description"] = r.Description
+		attrs["severity"] = r.Severity
+		attrs["aggregate_fields"] = sortedStrings(r.AggregateFields)
+		attrs["filter"] = r.Filter
+		attrs["window"] = r.Window
+		attrs["threshold"] = r.Threshold
+		attrs["suppressions"] = sortedStrings(r.Suppressions)
+		attrs["enabled"] = r.Enabled
+		tags = r.Tags
+	case *threatstack.FileRule:
+		attrs["name"] = r.Name
+		attrs["title"] = r.Title
+		attrs["description"] = r.Description
+		attrs["severity"] = r.Severity
+		attrs["aggregate_fields"] = sortedStrings(r.AggregateFields)
+		attrs["filter"] = r.Filter
+		attrs["window"] = r.Window
+		attrs["threshold"] = r.Threshold
+		attrs["suppressions"] = sortedStrings(r.Suppressions)
+		attrs["enabled"] = r.Enabled
+		attrs["ignore_files"] = sortedStrings(r.IgnoreFiles)
+		attrs["monitor_events"] = sortedStrings(r.MonitorEvents)
+
+		var paths []string
+		for _, v := range r.Paths {
+			paths = append(paths, fmt.Sprintf("%s (recursive=%t)", v.Path, v.Recursive))
+		}
+		attrs["file_path"] = sortedStrings(paths)
+		tags = r.Tags
+	}
+
+	if tags == nil {
+		tags = threatstack.NewTagSet()
+	}
+
+	var include, exclude []string
+	for _, v := range tags.Include {
+		include = append(include, fmt.Sprintf("%s:%s=%s", v.Source, v.Key, v.Value))
+	}
+	for _, v := range tags.Exclude {
+		exclude = append(exclude, fmt.Sprintf("%s:%s=%s", v.Source, v.Key, v.Value))
+	}
+	attrs["include_tag"] = sortedStrings(include)
+	attrs["exclude_tag"] = sortedStrings(exclude)
+
+	return attrs
+}
+
+// diffAttributes returns the sorted names of the live attributes whose value
+// differs from state.
+func diffAttributes(state map[string]interface{}, live map[string]interface{}) []string {
+	var ret []string
+	for k, v := range live {
+		stateKey := k
+		// Tags that came from the provider's default tags are only in
+		// include_tag_all and exclude_tag_all.
+		if _, ok := state[k+"_all"]; ok && (k == "include_tag" || k == "exclude_tag") {
+			stateKey = k + "_all"
+		}
+
+		if !reflect.DeepEqual(normalizeStateValue(k, state[stateKey]), v) {
+			ret = append(ret, k)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// normalizeStateValue converts a state attribute from its JSON form to the
+// form returned by liveRuleAttributes.
+func normalizeStateValue(key string, v interface{}) interface{} {
+	switch val := v.(type) {
+	case nil:
+		switch key {
+		case "name", "title", "description", "filter":
+			return ""
+		case "severity", "window", "threshold":
+			return 0
+		case "enabled":
+			return false
+		}
+		return []string{}
+	case float64:
+		return int(val)
+	case []interface{}:
+		var list []string
+		for _, item := range val {
+			switch obj := item.(type) {
+			case map[string]interface{}:
+				if key == "file_path" {
+					list = append(list, fmt.Sprintf("%v (recursive=%v)", obj["path"], obj["recursive"]))
+				} else {
+					list = append(list, fmt.Sprintf("%v:%v=%v", obj["source"], obj["key"], obj["value"]))
+				}
+			default:
+				list = append(list, fmt.Sprint(item))
+			}
+		}
+		return sortedStrings(list)
+	}
+	return v
+}
+
+func sortedStrings(list []string) []string {
+	ret := append([]string{}, list...)
+	sort.Strings(ret)
+	return ret
+}
+
+func stateString(attrs map[string]interface{}, key string) string {
+	if v, ok := attrs[key].(string); ok {
+		return v
+	}
+	return ""
+}
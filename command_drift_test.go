@@ -0,0 +1,117 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jfcantu/threatstack-golang/threatstack"
+)
+
+const testDriftState = `{
+  "version": 4,
+  "resources": [
+    {
+      "mode": "managed",
+      "type": "threatstack_ruleset",
+      "name": "test",
+      "instances": [
+        {"attributes": {"id": "rs1", "name": "rs", "description": "desc"}}
+      ]
+    },
+    {
+      "mode": "managed",
+      "type": "threatstack_host_rule",
+      "name": "test",
+      "instances": [
+        {
+          "attributes": {
+            "id": "r1",
+            "ruleset": "rs1",
+            "name": "host",
+            "title": "host",
+            "description": "",
+            "severity": 1,
+            "aggregate_fields": ["user"],
+            "filter": "event_type = \"host\"",
+            "window": 3600,
+            "threshold": 1,
+            "suppressions": [],
+            "enabled": true,
+            "include_tag": [{"source": "ec2", "key": "env", "value": "prod"}],
+            "exclude_tag": [],
+            "exclude_tag_all": [{"source": "ec2", "key": "env", "value": "sandbox"}]
+          }
+        }
+      ]
+    },
+    {
+      "mode": "managed",
+      "type": "threatstack_host_rule",
+      "name": "gone",
+      "instances": [
+        {"attributes": {"id": "r9", "ruleset": "rs1", "name": "gone"}}
+      ]
+    }
+  ]
+}`
+
+func TestNewDriftReport(test *testing.T) {
+	managed, err := parseState(strings.NewReader(testDriftState))
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	live := []*rulesetRules{
+		{
+			Ruleset: &threatstack.Ruleset{ID: "rs1", Name: "rs", Description: "desc"},
+			Rules: []threatstack.Rule{
+				&threatstack.HostRule{
+					ID:              "r1",
+					Type:            "Host",
+					Name:            "host",
+					Title:           "host",
+					Severity:        1,
+					AggregateFields: []string{"user"},
+					Filter:          `event_type = "host"`,
+					Window:          86400,
+					Threshold:       1,
+					Enabled:         true,
+					Tags: &threatstack.TagSet{
+						Include: []*threatstack.Tag{{Source: "ec2", Key: "env", Value: "prod"}},
+						Exclude: []*threatstack.Tag{{Source: "ec2", Key: "env", Value: "sandbox"}},
+					},
+				},
+				&threatstack.HostRule{ID: "r2", Type: "Host", Name: "shadow"},
+			},
+		},
+		{
+			Ruleset: &threatstack.Ruleset{ID: "rs2", Name: "other"},
+		},
+	}
+
+	report := newDriftReport(managed, live)
+
+	var unmanaged []string
+	for _, v := range report.Unmanaged {
+		unmanaged = append(unmanaged, v.ID)
+	}
+	if !reflect.DeepEqual(unmanaged, []string{"r2", "rs2"}) {
+		test.Errorf("Unmanaged: %v", unmanaged)
+	}
+
+	if len(report.Missing) != 1 || report.Missing[0].ID != "r9" || report.Missing[0].Address != "threatstack_host_rule.gone" {
+		test.Errorf("Missing: %#v", report.Missing)
+	}
+
+	if len(report.Drifted) != 1 || report.Drifted[0].ID != "r1" || !reflect.DeepEqual(report.Drifted[0].Fields, []string{"window"}) {
+		test.Errorf("Drifted: %#v", report.Drifted)
+	}
+}
+
+func TestParseStateVersion(test *testing.T) {
+	_, err := parseState(strings.NewReader(`{"version": 3}`))
+	if err == nil || !strings.Contains(err.Error(), "Unsupported state version") {
+		test.Errorf("Expected unsupported version error, got %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+func runFalcoCommand(args []string) int {
+	flags := flag.NewFlagSet("falco", flag.ContinueOnError)
+	ruleset := flags.String("ruleset", "threatstack_ruleset.falco.id", "Expression for the ruleset ID of the generated rules")
+	outFile := flags.String("out", "-", "File to write the generated configuration to, or - for stdout")
+	strict := flags.Bool("strict", false, "Exit with an error if any rule can't be converted")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if flags.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "At least one Falco rules file must be given")
+		return 1
+	}
+
+	var documents []string
+	for _, name := range flags.Args() {
+		content, err := ioutil.ReadFile(name)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			return 1
+		}
+		documents = append(documents, string(content))
+	}
+
+	conversion, err := convertFalcoRules(documents)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return 1
+	}
+
+	content := generateFalcoConfig(conversion, *ruleset)
+
+	if *outFile == "-" {
+		os.Stdout.Write(content)
+	} else if err := ioutil.WriteFile(*outFile, content, 0644); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return 1
+	}
+
+	for _, v := range conversion.Unsupported {
+		fmt.Fprintf(os.Stderr, "Skipped Falco rule %q: %s\n", v.Rule, v.Reason)
+	}
+
+	if *strict && len(conversion.Unsupported) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// generateFalcoConfig renders converted Falco rules as Terraform
+// configuration, listing the rules that couldn't be converted in a comment.
+func generateFalcoConfig(conversion *falcoConversion, rulesetRef string) []byte {
+	buf := new(bytes.Buffer)
+	names := newHCLNames()
+
+	if len(conversion.Unsupported) > 0 {
+		buf.WriteString("# The following Falco rules could not be converted:\n")
+		for _, v := range conversion.Unsupported {
+			fmt.Fprintf(buf, "#   %s: %s\n", v.Rule, v.Reason)
+		}
+	}
+
+	for _, v := range conversion.HostRules {
+		block := newHCLBlock("resource", "threatstack_host_rule", names.unique("threatstack_host_rule", v.Name))
+		generateHostRule(block, v, rulesetRef)
+		if buf.Len() > 0 {
+			buf.WriteString("\n")
+		}
+		block.write(buf, 0)
+	}
+
+	for _, v := range conversion.FileRules {
+		block := newHCLBlock("resource", "threatstack_file_rule", names.unique("threatstack_file_rule", v.Name))
+		generateFileRule(block, v, rulesetRef)
+		if buf.Len() > 0 {
+			buf.WriteString("\n")
+		}
+		block.write(buf, 0)
+	}
+
+	return buf.Bytes()
+}
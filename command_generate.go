@@ -0,0 +1,297 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/jfcantu/threatstack-golang/threatstack"
+)
+
+func runGenerateCommand(args []string) int {
+	flags := flag.NewFlagSet("generate", flag.ContinueOnError)
+	outDir := flags.String("out", ".", "Directory to write .tf files and import.sh to")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	client, err := commandClient()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return 1
+	}
+
+	rulesets, err := listRulesetRules(client)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return 1
+	}
+
+	files, imports := generateConfig(rulesets, os.Stderr)
+
+	if err := os.MkdirAll(*outDir, 0755); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return 1
+	}
+
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(*outDir, name), content, 0644); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			return 1
+		}
+	}
+
+	script := "#!/bin/sh\nset -e\n\n" + strings.Join(imports, "\n") + "\n"
+	if err := ioutil.WriteFile(filepath.Join(*outDir, "import.sh"), []byte(script), 0755); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return 1
+	}
+
+	fmt.Printf("Wrote %d rulesets and %d import commands to %s\n", len(files), len(imports), *outDir)
+	return 0
+}
+
+// generateConfig renders one .tf file per ruleset, keyed by file name, along
+// with the "terraform import" commands for every generated resource. Rules of
+// types without a dedicated resource are skipped with a warning.
+func generateConfig(rulesets []*rulesetRules, warnings io.Writer) (map[string][]byte, []string) {
+	files := map[string][]byte{}
+	var imports []string
+	names := newHCLNames()
+
+	for _, rs := range rulesets {
+		buf := new(bytes.Buffer)
+
+		rsName := names.unique("threatstack_ruleset", rs.Ruleset.Name)
+		block := newHCLBlock("resource", "threatstack_ruleset", rsName)
+		block.attr("name", hclString(rs.Ruleset.Name))
+		block.attr("description", hclString(rs.Ruleset.Description))
+		block.write(buf, 0)
+
+		imports = append(imports, fmt.Sprintf("terraform import threatstack_ruleset.%s %s", rsName, rs.Ruleset.ID))
+		rulesetRef := fmt.Sprintf("threatstack_ruleset.%s.id", rsName)
+
+		for _, rule := range rs.Rules {
+			var resourceType string
+			var ruleBlock *hclBlock
+
+			switch r := rule.(type) {
+			case *threatstack.HostRule:
+				if r.Type != "Host" {
+					if warnings != nil {
+						fmt.Fprintf(warnings, "Skipping rule %s (%s): %s rules are not supported\n", r.ID, r.Name, r.Type)
+					}
+					continue
+				}
+				resourceType = "threatstack_host_rule"
+				ruleBlock = newHCLBlock("resource", resourceType, names.unique(resourceType, r.Name))
+				generateHostRule(ruleBlock, r, rulesetRef)
+			case *threatstack.FileRule:
+				resourceType = "threatstack_file_rule"
+				ruleBlock = newHCLBlock("resource", resourceType, names.unique(resourceType, r.Name))
+				generateFileRule(ruleBlock, r, rulesetRef)
+			default:
+				continue
+			}
+
+			buf.WriteString("\n")
+			ruleBlock.write(buf, 0)
+
+			imports = append(imports, fmt.Sprintf("terraform import %s.%s %s/%s",
+				resourceType, ruleBlock.labels[2], rs.Ruleset.ID, rule.GetID()))
+		}
+
+		files[rsName+".tf"] = buf.Bytes()
+	}
+
+	return files, imports
+}
+
+func generateHostRule(block *hclBlock, rule *threatstack.HostRule, rulesetRef string) {
+	generateCommonRuleAttrs(block, rule.Name, rule.Title, rule.Description, rulesetRef, rule.Severity,
+		rule.AggregateFields, rule.Filter, rule.Window, rule.Threshold, rule.Suppressions, rule.Enabled)
+	generateTagBlocks(block, rule.Tags)
+}
+
+func generateFileRule(block *hclBlock, rule *threatstack.FileRule, rulesetRef string) {
+	generateCommonRuleAttrs(block, rule.Name, rule.Title, rule.Description, rulesetRef, rule.Severity,
+		rule.AggregateFields, rule.Filter, rule.Window, rule.Threshold, rule.Suppressions, rule.Enabled)
+	if len(rule.IgnoreFiles) > 0 {
+		block.attr("ignore_files", hclStringList(rule.IgnoreFiles))
+	}
+	block.attr("monitor_events", hclStringList(rule.MonitorEvents))
+
+	for _, v := range rule.Paths {
+		path := newHCLBlock("file_path")
+		path.attr("path", hclString(v.Path))
+		path.attr("recursive", strconv.FormatBool(v.Recursive))
+		block.block(path)
+	}
+
+	generateTagBlocks(block, rule.Tags)
+}
+
+func generateCommonRuleAttrs(block *hclBlock, name, title, desc, rulesetRef string, severity int,
+	aggregate []string, filter string, window, threshold int, suppressions []string, enabled bool) {
+	block.attr("name", hclString(name))
+	block.attr("title", hclString(title))
+	if desc != "" {
+		block.attr("description", hclString(desc))
+	}
+	block.attr("ruleset", rulesetRef)
+	block.attr("severity", strconv.Itoa(severity))
+	if len(aggregate) > 0 {
+		block.attr("aggregate_fields", hclStringList(aggregate))
+	}
+	block.attr("filter", hclString(filter))
+	block.attr("window", strconv.Itoa(window))
+	block.attr("threshold", strconv.Itoa(threshold))
+	if len(suppressions) > 0 {
+		block.attr("suppressions", hclStringList(suppressions))
+	}
+	block.attr("enabled", strconv.FormatBool(enabled))
+}
+
+func generateTagBlocks(block *hclBlock, tags *threatstack.TagSet) {
+	if tags == nil {
+		return
+	}
+
+	for _, v := range tags.Include {
+		tag := newHCLBlock("include_tag")
+		tag.attr("source", hclString(v.Source))
+		tag.attr("key", hclString(v.Key))
+		tag.attr("value", hclString(v.Value))
+		block.block(tag)
+	}
+	for _, v := range tags.Exclude {
+		tag := newHCLBlock("exclude_tag")
+		tag.attr("source", hclString(v.Source))
+		tag.attr("key", hclString(v.Key))
+		tag.attr("value", hclString(v.Value))
+		block.block(tag)
+	}
+}
+
+// hclBlock is a minimal HCL block writer. Attribute values are written
+// verbatim, so they must already be valid HCL expressions.
+type hclBlock struct {
+	labels []string
+	attrs  [][2]string
+	blocks []*hclBlock
+}
+
+func newHCLBlock(labels ...string) *hclBlock {
+	return &hclBlock{labels: labels}
+}
+
+func (b *hclBlock) attr(name, value string) {
+	b.attrs = append(b.attrs, [2]string{name, value})
+}
+
+func (b *hclBlock) block(child *hclBlock) {
+	b.blocks = append(b.blocks, child)
+}
+
+// write renders the block in "terraform fmt" style.
+func (b *hclBlock) write(buf *bytes.Buffer, depth int) {
+	indent := strings.Repeat("  ", depth)
+
+	buf.WriteString(indent + b.labels[0])
+	for _, v := range b.labels[1:] {
+		buf.WriteString(" " + strconv.Quote(v))
+	}
+	buf.WriteString(" {\n")
+
+	width := 0
+	for _, v := range b.attrs {
+		if len(v[0]) > width {
+			width = len(v[0])
+		}
+	}
+	for _, v := range b.attrs {
+		fmt.Fprintf(buf, "%s  %-*s = %s\n", indent, width, v[0], v[1])
+	}
+
+	for _, v := range b.blocks {
+		buf.WriteString("\n")
+		v.write(buf, depth+1)
+	}
+
+	buf.WriteString(indent + "}\n")
+}
+
+// hclString quotes a string for HCL, escaping "${" and "%{" so that filters
+// and alert titles are never interpreted as template sequences.
+func hclString(s string) string {
+	buf := new(bytes.Buffer)
+	buf.WriteByte('"')
+	for i, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			buf.WriteRune('\\')
+			buf.WriteRune(r)
+		case r == '\n':
+			buf.WriteString(`\n`)
+		case r == '\r':
+			buf.WriteString(`\r`)
+		case r == '\t':
+			buf.WriteString(`\t`)
+		case (r == '$' || r == '%') && strings.HasPrefix(s[i+1:], "{"):
+			buf.WriteRune(r)
+			buf.WriteRune(r)
+		case !unicode.IsPrint(r) && r > 0xffff:
+			fmt.Fprintf(buf, `\U%08x`, r)
+		case !unicode.IsPrint(r):
+			fmt.Fprintf(buf, `\u%04x`, r)
+		default:
+			buf.WriteRune(r)
+		}
+	}
+	buf.WriteByte('"')
+	return buf.String()
+}
+
+func hclStringList(list []string) string {
+	var quoted []string
+	for _, v := range list {
+		quoted = append(quoted, hclString(v))
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+var hclInvalidNameChars = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// hclNames hands out unique resource names per resource type.
+type hclNames map[string]map[string]bool
+
+func newHCLNames() hclNames {
+	return hclNames{}
+}
+
+func (n hclNames) unique(resourceType, name string) string {
+	base := strings.Trim(hclInvalidNameChars.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if base == "" || (base[0] >= '0' && base[0] <= '9') {
+		base = "r_" + base
+	}
+
+	if n[resourceType] == nil {
+		n[resourceType] = map[string]bool{}
+	}
+
+	ret := base
+	for i := 2; n[resourceType][ret]; i++ {
+		ret = fmt.Sprintf("%s_%d", base, i)
+	}
+	n[resourceType][ret] = true
+
+	return ret
+}
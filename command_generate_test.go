@@ -0,0 +1,98 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jfcantu/threatstack-golang/threatstack"
+)
+
+func TestGenerateConfig(test *testing.T) {
+	rulesets := []*rulesetRules{
+		{
+			Ruleset: &threatstack.Ruleset{ID: "rs1", Name: "Base Rule Set", Description: "Base rules"},
+			Rules: []threatstack.Rule{
+				&threatstack.HostRule{
+					ID:           "r1",
+					Type:         "Host",
+					Name:         "Host: New User",
+					Title:        "New user {{user}}",
+					Severity:     1,
+					Filter:       `event_type = "host"`,
+					Window:       3600,
+					Threshold:    1,
+					Suppressions: []string{`user = "${chef}"`},
+					Enabled:      true,
+					Tags: &threatstack.TagSet{
+						Include: []*threatstack.Tag{{Source: "ec2", Key: "env", Value: "prod"}},
+					},
+				},
+				&threatstack.FileRule{
+					ID:            "r2",
+					Type:          "File",
+					Name:          "Host: New User",
+					Title:         "File",
+					Severity:      2,
+					Window:        86400,
+					Threshold:     1,
+					Paths:         []*threatstack.FilePath{{Path: "/etc", Recursive: true}},
+					MonitorEvents: []string{"open"},
+				},
+				&threatstack.HostRule{ID: "r3", Type: "CloudTrail", Name: "Skipped"},
+			},
+		},
+	}
+
+	files, imports := generateConfig(rulesets, nil)
+
+	content, ok := files["base_rule_set.tf"]
+	if !ok {
+		test.Fatalf("Expected base_rule_set.tf, got %v", files)
+	}
+
+	for _, expected := range []string{
+		`resource "threatstack_ruleset" "base_rule_set" {`,
+		`resource "threatstack_host_rule" "host_new_user" {`,
+		`resource "threatstack_file_rule" "host_new_user" {`,
+		`  ruleset      = threatstack_ruleset.base_rule_set.id`,
+		`  title        = "New user {{user}}"`,
+		`  suppressions = ["user = \"$${chef}\""]`,
+		`    source = "ec2"`,
+		`    recursive = true`,
+	} {
+		if !strings.Contains(string(content), expected) {
+			test.Errorf("Expected generated config to contain %q:\n%s", expected, content)
+		}
+	}
+
+	if strings.Contains(string(content), "Skipped") {
+		test.Errorf("Expected CloudTrail rule to be skipped:\n%s", content)
+	}
+
+	expectedImports := []string{
+		"terraform import threatstack_ruleset.base_rule_set rs1",
+		"terraform import threatstack_host_rule.host_new_user rs1/r1",
+		"terraform import threatstack_file_rule.host_new_user rs1/r2",
+	}
+	if strings.Join(imports, "\n") != strings.Join(expectedImports, "\n") {
+		test.Errorf("Imports:\n%v\n\nExpected:\n%v", imports, expectedImports)
+	}
+}
+
+func TestHCLNamesUnique(test *testing.T) {
+	names := newHCLNames()
+
+	for _, v := range []struct {
+		name     string
+		expected string
+	}{
+		{"Host: New User", "host_new_user"},
+		{"host new user", "host_new_user_2"},
+		{"1 rule", "r_1_rule"},
+		{"!!!", "r_"},
+	} {
+		if got := names.unique("threatstack_host_rule", v.name); got != v.expected {
+			test.Errorf("unique(%q) = %q, expected %q", v.name, got, v.expected)
+		}
+	}
+}
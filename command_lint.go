@@ -0,0 +1,129 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+func runLintCommand(args []string) int {
+	flags := flag.NewFlagSet("lint", flag.ContinueOnError)
+	pack := flags.String("pack", "", "Lint the rule pack in this directory instead of the rules in Threat Stack")
+	ignore := flags.String("ignore", "", "Comma-separated lint checks to skip")
+	severity := flags.String("severity", "", "Comma-separated check=severity overrides, e.g. fim-root=error")
+	jsonOutput := flags.Bool("json", false, "Write the findings as JSON")
+	listChecks := flags.Bool("checks", false, "List the available lint checks and exit")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if *listChecks {
+		writeLintChecks(os.Stdout)
+		return 0
+	}
+
+	config, err := parseLintFlags(*ignore, *severity)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return 1
+	}
+
+	var findings []*lintFinding
+	if *pack != "" {
+		entries, err := readRulePack(*pack)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			return 1
+		}
+
+		for _, entry := range entries {
+			for _, v := range config.lintRule(entry.Rule.threatstackRule(), nil) {
+				v.Rule = entry.File
+				findings = append(findings, v)
+			}
+		}
+	} else {
+		client, err := commandClient()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			return 1
+		}
+
+		rulesets, err := listRulesetRules(client)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			return 1
+		}
+
+		for _, ruleset := range rulesets {
+			for _, rule := range ruleset.Rules {
+				name, _ := liveRuleAttributes(rule)["name"].(string)
+				if name == "" {
+					name = rule.GetID()
+				}
+
+				for _, v := range config.lintRule(rule, nil) {
+					v.Rule = ruleset.Ruleset.Name + "/" + name
+					findings = append(findings, v)
+				}
+			}
+		}
+	}
+
+	sortLintFindings(findings)
+
+	if *jsonOutput {
+		if findings == nil {
+			findings = []*lintFinding{}
+		}
+		if err := writeCommandJSON("-", findings); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			return 1
+		}
+	} else {
+		for _, v := range findings {
+			fmt.Fprintln(os.Stdout, v.String())
+		}
+	}
+
+	for _, v := range findings {
+		if v.Severity == lintSeverityError {
+			return 2
+		}
+	}
+	return 0
+}
+
+// parseLintFlags builds a lint configuration from the -ignore and -severity
+// flags.
+func parseLintFlags(ignore, severity string) (*lintConfig, error) {
+	var ignoreList []string
+	for _, v := range strings.Split(ignore, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			ignoreList = append(ignoreList, v)
+		}
+	}
+
+	severityMap := map[string]string{}
+	for _, v := range strings.Split(severity, ",") {
+		if v = strings.TrimSpace(v); v == "" {
+			continue
+		}
+
+		parts := strings.SplitN(v, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("Invalid severity override %q, expected check=severity", v)
+		}
+		severityMap[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+
+	return newLintConfig(ignoreList, severityMap)
+}
+
+func writeLintChecks(w io.Writer) {
+	for _, v := range lintChecks {
+		fmt.Fprintf(w, "%-14s %-8s %s\n", v.Name, v.Severity, v.Description)
+	}
+}
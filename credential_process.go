@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// credentialProcessTimeout is how long a credential process may run.
+const credentialProcessTimeout = time.Minute
+
+// credentialProcessExpiryMargin is how long before they expire credentials
+// from a credential process are refreshed, so that requests in flight don't
+// use expired credentials.
+const credentialProcessExpiryMargin = time.Minute
+
+// credentialProcessOutput is the JSON a credential process writes to
+// standard output. The credentials are required; Expiration is optional, and
+// is an RFC 3339 timestamp.
+type credentialProcessOutput struct {
+	APIKey         string `json:"api_key"`
+	OrganizationID string `json:"organization_id"`
+	UserID         string `json:"user_id"`
+	Expiration     string `json:"expiration"`
+}
+
+// credentialProcess is an external command that supplies credentials, e.g.
+// from a secrets manager.
+type credentialProcess struct {
+	Command string
+	// Expires is when the last credentials returned expire. It's zero if
+	// they don't.
+	Expires time.Time
+}
+
+// Expired returns whether the credentials need to be refreshed.
+func (p *credentialProcess) Expired() bool {
+	return !p.Expires.IsZero() && time.Now().Add(credentialProcessExpiryMargin).After(p.Expires)
+}
+
+// Source runs the command and returns its credentials. The command is run
+// by the shell, so it may contain arguments and quotes.
+func (p *credentialProcess) Source() (*credentialSource, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), credentialProcessTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", p.Command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", p.Command)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("credential_process timed out after %s", credentialProcessTimeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("credential_process failed: %s: %s", err.Error(), msg)
+		}
+		return nil, fmt.Errorf("credential_process failed: %s", err.Error())
+	}
+
+	output := new(credentialProcessOutput)
+	if err := json.Unmarshal(stdout.Bytes(), output); err != nil {
+		return nil, fmt.Errorf("Error parsing credential_process output, expected a JSON object: %s", err.Error())
+	}
+
+	var missing []string
+	if output.APIKey == "" {
+		missing = append(missing, "api_key")
+	}
+	if output.OrganizationID == "" {
+		missing = append(missing, "organization_id")
+	}
+	if output.UserID == "" {
+		missing = append(missing, "user_id")
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("credential_process output is missing %s", strings.Join(missing, ", "))
+	}
+
+	p.Expires = time.Time{}
+	if output.Expiration != "" {
+		expires, err := time.Parse(time.RFC3339, output.Expiration)
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing credential_process expiration: %s", err.Error())
+		}
+		// Credentials inside the expiry margin would be refreshed on every
+		// request.
+		if !expires.After(time.Now().Add(credentialProcessExpiryMargin)) {
+			return nil, fmt.Errorf("credential_process returned credentials that expire at %s, less than %s from now", output.Expiration, credentialProcessExpiryMargin)
+		}
+		p.Expires = expires
+	}
+
+	return &credentialSource{
+		Name: "credential_process",
+		Config: Config{
+			APIKey:         output.APIKey,
+			OrganizationID: output.OrganizationID,
+			UserID:         output.UserID,
+		},
+	}, nil
+}
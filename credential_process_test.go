@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/jfcantu/threatstack-golang/threatstack"
+)
+
+func testCredentialProcess(test *testing.T, output string) *credentialProcess {
+	if runtime.GOOS == "windows" {
+		test.Skip("credential process tests use sh")
+	}
+	return &credentialProcess{Command: fmt.Sprintf("printf '%%s' '%s'", output)}
+}
+
+func testCredentialProcessOutput(expiration string) string {
+	return fmt.Sprintf(`{"api_key": "key", "organization_id": "org", "user_id": "user", "expiration": %q}`, expiration)
+}
+
+func TestCredentialProcessSource(test *testing.T) {
+	expiration := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	process := testCredentialProcess(test, fmt.Sprintf(`{"api_key": "key", "organization_id": "org", "user_id": "user", "expiration": %q}`, expiration.Format(time.RFC3339)))
+
+	source, err := process.Source()
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	expected := Config{APIKey: "key", OrganizationID: "org", UserID: "user"}
+	if source.Config != expected {
+		test.Errorf("Expected %+v, got %+v", expected, source.Config)
+	}
+	if !process.Expires.Equal(expiration) {
+		test.Errorf("Expected the credentials to expire at %s, got %s", expiration, process.Expires)
+	}
+	if process.Expired() {
+		test.Error("Expected the credentials not to need refreshing")
+	}
+
+	process.Expires = time.Now().Add(credentialProcessExpiryMargin / 2)
+	if !process.Expired() {
+		test.Error("Expected credentials about to expire to need refreshing")
+	}
+}
+
+func TestCredentialProcessErrors(test *testing.T) {
+	testCredentialProcess(test, "")
+
+	for _, v := range []struct {
+		command string
+		message string
+	}{
+		{"echo 'no such key' >&2; exit 1", "no such key"},
+		{"echo not-json", "expected a JSON object"},
+		{`echo '{"api_key": "key"}'`, "missing organization_id, user_id"},
+		{fmt.Sprintf(`echo '%s'`, testCredentialProcessOutput("tomorrow")), "expiration"},
+		{fmt.Sprintf(`echo '%s'`, testCredentialProcessOutput("2000-01-01T00:00:00Z")), "expire at 2000-01-01T00:00:00Z"},
+		{fmt.Sprintf(`echo '%s'`, testCredentialProcessOutput(time.Now().Add(credentialProcessExpiryMargin/2).UTC().Format(time.RFC3339))), "less than 1m0s from now"},
+	} {
+		_, err := (&credentialProcess{Command: v.command}).Source()
+		if err == nil || !strings.Contains(err.Error(), v.message) {
+			test.Errorf("Expected an error mentioning %q for %s, got %v", v.message, v.command, err)
+		}
+	}
+}
+
+func TestProviderMetaRefreshCredentials(test *testing.T) {
+	process := testCredentialProcess(test, `{"api_key": "new-key", "organization_id": "org", "user_id": "user"}`)
+	process.Expires = time.Now().Add(time.Hour)
+
+	oldClient := &threatstack.Client{}
+	processSource := &credentialSource{Name: "credential_process", Config: Config{APIKey: "old-key", OrganizationID: "org", UserID: "user"}}
+	meta := &providerMeta{
+		Client: oldClient,
+		config: Config{APIKey: "old-key", OrganizationID: "org", UserID: "user"},
+		sources: []*credentialSource{
+			{Name: "provider arguments"},
+			processSource,
+			{Name: "environment variables"},
+		},
+		process:       process,
+		processSource: processSource,
+		clients:       map[string]*threatstack.Client{"staging": {}},
+	}
+
+	client, err := meta.OrgClient("")
+	if err != nil {
+		test.Fatal(err)
+	}
+	if client != oldClient {
+		test.Error("Expected the client to be kept until the credentials expire")
+	}
+
+	process.Expires = time.Now()
+
+	client, err = meta.OrgClient("")
+	if err != nil {
+		test.Fatal(err)
+	}
+	if client == oldClient {
+		test.Error("Expected a new client after the credentials expired")
+	}
+	if meta.config.APIKey != "new-key" {
+		test.Errorf("Expected the refreshed API key, got %q", meta.config.APIKey)
+	}
+	if meta.sources[1] != meta.processSource || meta.processSource.Config.APIKey != "new-key" {
+		test.Errorf("Expected the process's source to be replaced, got %+v", meta.sources[1])
+	}
+	if len(meta.clients) != 0 {
+		test.Errorf("Expected the clients for other organizations to be dropped, got %d", len(meta.clients))
+	}
+}
+
+func TestProviderCredentialSourcesLazy(test *testing.T) {
+	testCredentialProcess(test, "")
+
+	data := schema.TestResourceDataRaw(test, Provider().Schema, map[string]interface{}{
+		"api_key":                 "key",
+		"organization_id":         "org",
+		"user_id":                 "user",
+		"credential_process":      "echo 'should not run' >&2; exit 1",
+		"profile":                 "missing",
+		"shared_credentials_file": "/nonexistent/credentials",
+	})
+
+	sources, process, _, err := providerCredentialSources(data)
+	if err != nil {
+		test.Fatalf("Expected the credential process and profile not to be read, got %s", err)
+	}
+	if len(sources) != 1 || process != nil {
+		test.Errorf("Expected only the provider arguments to be read, got %d sources", len(sources))
+	}
+
+	data = schema.TestResourceDataRaw(test, Provider().Schema, map[string]interface{}{
+		"credential_process": fmt.Sprintf("printf '%%s' '%s'", testCredentialProcessOutput("")),
+	})
+
+	sources, process, processSource, err := providerCredentialSources(data)
+	if err != nil {
+		test.Fatal(err)
+	}
+	if process == nil || len(sources) != 2 || sources[1] != processSource {
+		test.Errorf("Expected the credential process to be read after the empty provider arguments, got %d sources", len(sources))
+	}
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// defaultCredentialsFile is the shared credentials file, relative to the
+// user's home directory.
+var defaultCredentialsFile = filepath.Join(".threatstack", "credentials")
+
+const defaultCredentialsProfile = "default"
+
+// credentialSource is one place credentials can come from. Any of the
+// fields of Config may be empty.
+type credentialSource struct {
+	// Name describes the source in errors.
+	Name   string
+	Config Config
+	// Note explains why the source has no credentials, e.g. a missing file.
+	Note string
+}
+
+// resolveCredentials builds the client configuration from sources. All of
+// api_key, organization_id and user_id are taken from the first source that
+// sets any of them, so that a key is never paired with another source's
+// organization or user; that source must set all three.
+//
+// The provider checks, in order:
+//
+//  1. The api_key, organization_id and user_id provider arguments.
+//  2. The output of the command in the credential_process argument or
+//     THREATSTACK_CREDENTIAL_PROCESS, if either is set.
+//  3. The THREATSTACK_API_KEY, THREATSTACK_ORG_ID and THREATSTACK_USER_ID
+//     environment variables.
+//  4. The profile named by the profile argument or THREATSTACK_PROFILE
+//     ("default" if neither is set), in the file named by the
+//     shared_credentials_file argument or
+//     THREATSTACK_SHARED_CREDENTIALS_FILE (~/.threatstack/credentials if
+//     neither is set).
+//
+// Commands check the same sources except the provider arguments.
+func resolveCredentials(sources []*credentialSource) (*Config, error) {
+	var checked []string
+	for _, v := range sources {
+		if v.Config == (Config{}) {
+			if v.Note != "" {
+				checked = append(checked, fmt.Sprintf("  %s: %s", v.Name, v.Note))
+			} else {
+				checked = append(checked, "  "+v.Name)
+			}
+			continue
+		}
+
+		var missing []string
+		if v.Config.APIKey == "" {
+			missing = append(missing, "api_key")
+		}
+		if v.Config.OrganizationID == "" {
+			missing = append(missing, "organization_id")
+		}
+		if v.Config.UserID == "" {
+			missing = append(missing, "user_id")
+		}
+		if len(missing) > 0 {
+			return nil, fmt.Errorf("Incomplete Threat Stack credentials from %s: missing %s. Credentials are not combined from different sources",
+				v.Name, strings.Join(missing, ", "))
+		}
+
+		config := v.Config
+		return &config, nil
+	}
+
+	return nil, fmt.Errorf("Missing Threat Stack credentials\nSources checked, in order:\n%s", strings.Join(checked, "\n"))
+}
+
+// envCredentialSource returns the credentials in the environment.
+func envCredentialSource() *credentialSource {
+	return &credentialSource{
+		Name: "environment variables THREATSTACK_API_KEY, THREATSTACK_ORG_ID and THREATSTACK_USER_ID",
+		Config: Config{
+			APIKey:         os.Getenv("THREATSTACK_API_KEY"),
+			OrganizationID: os.Getenv("THREATSTACK_ORG_ID"),
+			UserID:         os.Getenv("THREATSTACK_USER_ID"),
+		},
+	}
+}
+
+// profileCredentialSource returns the credentials in a profile of the
+// shared credentials file. An empty file or profile means the default. A
+// missing file or profile is only an error if the profile was asked for
+// explicitly; otherwise the source is just empty.
+func profileCredentialSource(file, profile string) (*credentialSource, error) {
+	explicit := profile != ""
+	if !explicit {
+		profile = defaultCredentialsProfile
+	}
+
+	if file == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("Error finding the shared credentials file: %s", err.Error())
+		}
+		file = filepath.Join(home, defaultCredentialsFile)
+	}
+
+	source := &credentialSource{
+		Name: fmt.Sprintf("profile %q in %s", profile, file),
+	}
+
+	profiles, err := readCredentialsFile(file)
+	if os.IsNotExist(err) {
+		if explicit {
+			return nil, fmt.Errorf("Profile %q was requested, but the shared credentials file %s doesn't exist", profile, file)
+		}
+		source.Note = "file not found"
+		return source, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	values, ok := profiles[profile]
+	if !ok {
+		var names []string
+		for k := range profiles {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+
+		if explicit {
+			return nil, fmt.Errorf("Profile %q not found in %s (profiles: %s)", profile, file, strings.Join(names, ", "))
+		}
+		source.Note = "profile not found"
+		return source, nil
+	}
+
+	source.Config = Config{
+		APIKey:         values["api_key"],
+		OrganizationID: values["organization_id"],
+		UserID:         values["user_id"],
+	}
+
+	return source, nil
+}
+
+// readCredentialsFile parses a shared credentials file into its profiles.
+// The file is INI-style: "[profile]" section headers followed by
+// "key = value" lines. Values may be quoted, and lines starting with "#" or
+// ";" are comments.
+func readCredentialsFile(file string) (map[string]map[string]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	profiles := map[string]map[string]string{}
+	var current map[string]string
+
+	scanner := bufio.NewScanner(f)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			if name == "" {
+				return nil, fmt.Errorf("%s:%d: empty profile name", file, lineNum)
+			}
+			if _, ok := profiles[name]; !ok {
+				profiles[name] = map[string]string{}
+			}
+			current = profiles[name]
+			continue
+		}
+
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("%s:%d: expected \"key = value\" or \"[profile]\"", file, lineNum)
+		}
+		if current == nil {
+			return nil, fmt.Errorf("%s:%d: %q is not in a profile", file, lineNum, strings.TrimSpace(parts[0]))
+		}
+
+		value := strings.TrimSpace(parts[1])
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		current[strings.TrimSpace(parts[0])] = value
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("Error reading %s: %s", file, err.Error())
+	}
+
+	return profiles, nil
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testCredentialsFile = `
+# Shared Threat Stack credentials
+[default]
+api_key = default-key
+organization_id = default-org
+user_id = default-user
+
+[staging]
+api_key = "staging-key"
+organization_id = 'staging-org'
+; user_id comes from somewhere else
+`
+
+func testCredentialsDir(test *testing.T) string {
+	dir, err := ioutil.TempDir("", "credentials")
+	if err != nil {
+		test.Fatal(err)
+	}
+	test.Cleanup(func() { os.RemoveAll(dir) })
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "credentials"), []byte(testCredentialsFile), 0600); err != nil {
+		test.Fatal(err)
+	}
+	return dir
+}
+
+// testUnsetCredentialsEnv clears the credential environment variables for the
+// duration of a test.
+func testUnsetCredentialsEnv(test *testing.T) {
+	for _, k := range []string{"THREATSTACK_API_KEY", "THREATSTACK_ORG_ID", "THREATSTACK_USER_ID"} {
+		if v, ok := os.LookupEnv(k); ok {
+			k := k
+			test.Cleanup(func() { os.Setenv(k, v) })
+		}
+		os.Unsetenv(k)
+	}
+}
+
+func TestReadCredentialsFile(test *testing.T) {
+	file := filepath.Join(testCredentialsDir(test), "credentials")
+
+	profiles, err := readCredentialsFile(file)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	expected := map[string]map[string]string{
+		"default": {"api_key": "default-key", "organization_id": "default-org", "user_id": "default-user"},
+		"staging": {"api_key": "staging-key", "organization_id": "staging-org"},
+	}
+	if !reflect.DeepEqual(profiles, expected) {
+		test.Errorf("Expected %v, got %v", expected, profiles)
+	}
+
+	if err := ioutil.WriteFile(file, []byte("api_key = key\n"), 0600); err != nil {
+		test.Fatal(err)
+	}
+	if _, err := readCredentialsFile(file); err == nil || !strings.Contains(err.Error(), ":1:") {
+		test.Errorf("Expected an error with the line number for a key outside a profile, got %v", err)
+	}
+}
+
+func TestResolveCredentialsOrder(test *testing.T) {
+	testUnsetCredentialsEnv(test)
+	file := filepath.Join(testCredentialsDir(test), "credentials")
+
+	os.Setenv("THREATSTACK_API_KEY", "env-key")
+	os.Setenv("THREATSTACK_ORG_ID", "env-org")
+	os.Setenv("THREATSTACK_USER_ID", "env-user")
+
+	profile, err := profileCredentialSource(file, "")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	config, err := resolveCredentials([]*credentialSource{
+		{Name: "provider arguments"},
+		envCredentialSource(),
+		profile,
+	})
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	expected := &Config{APIKey: "env-key", OrganizationID: "env-org", UserID: "env-user"}
+	if !reflect.DeepEqual(config, expected) {
+		test.Errorf("Expected %+v, got %+v", expected, config)
+	}
+}
+
+func TestResolveCredentialsIncomplete(test *testing.T) {
+	testUnsetCredentialsEnv(test)
+	file := filepath.Join(testCredentialsDir(test), "credentials")
+
+	os.Setenv("THREATSTACK_ORG_ID", "env-org")
+
+	profile, err := profileCredentialSource(file, "")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	// The complete default profile isn't used to fill in the environment.
+	_, err = resolveCredentials([]*credentialSource{
+		{Name: "provider arguments"},
+		envCredentialSource(),
+		profile,
+	})
+	if err == nil {
+		test.Fatal("Expected an error for incomplete credentials")
+	}
+
+	for _, v := range []string{"environment variables", "api_key, user_id"} {
+		if !strings.Contains(err.Error(), v) {
+			test.Errorf("Expected the error to mention %q, got %q", v, err.Error())
+		}
+	}
+}
+
+func TestProfileCredentialSourceMissing(test *testing.T) {
+	dir := testCredentialsDir(test)
+
+	if _, err := profileCredentialSource(filepath.Join(dir, "credentials"), "production"); err == nil || !strings.Contains(err.Error(), "default, staging") {
+		test.Errorf("Expected an error listing the profiles for an unknown profile, got %v", err)
+	}
+
+	if _, err := profileCredentialSource(filepath.Join(dir, "missing"), "staging"); err == nil || !strings.Contains(err.Error(), "doesn't exist") {
+		test.Errorf("Expected an error for an explicit profile in a missing file, got %v", err)
+	}
+
+	source, err := profileCredentialSource(filepath.Join(dir, "missing"), "")
+	if err != nil {
+		test.Fatal(err)
+	}
+	if source.Note != "file not found" || source.Config != (Config{}) {
+		test.Errorf("Expected an empty source for the default profile in a missing file, got %+v", source)
+	}
+}
+
+func TestResolveCredentialsMissing(test *testing.T) {
+	testUnsetCredentialsEnv(test)
+	dir := testCredentialsDir(test)
+
+	profile, err := profileCredentialSource(filepath.Join(dir, "missing"), "")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	_, err = resolveCredentials([]*credentialSource{
+		{Name: "provider arguments"},
+		envCredentialSource(),
+		profile,
+	})
+	if err == nil {
+		test.Fatal("Expected an error for missing credentials")
+	}
+
+	for _, v := range []string{"Missing Threat Stack credentials", "provider arguments", "THREATSTACK_ORG_ID", `profile "default"`, "file not found"} {
+		if !strings.Contains(err.Error(), v) {
+			test.Errorf("Expected the error to mention %q, got %q", v, err.Error())
+		}
+	}
+}
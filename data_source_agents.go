@@ -0,0 +1,196 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/jfcantu/threatstack-golang/threatstack"
+)
+
+func dataSourceAgents() *schema.Resource {
+	stringList := &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem:     &schema.Schema{Type: schema.TypeString},
+	}
+
+	return &schema.Resource{
+		Read: dataSourceAgentsRead,
+
+		Schema: map[string]*schema.Schema{
+			"organization_id": dataSourceOrganizationIDSchema(),
+			"status": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ValidateFunc:  validation.StringInSlice(agentStatuses, false),
+				ConflictsWith: []string{"online"},
+			},
+			"online": &schema.Schema{
+				Type:          schema.TypeBool,
+				Optional:      true,
+				ConflictsWith: []string{"status"},
+			},
+			"hostname": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+			},
+			"tag": ruleTagSchema(),
+			"ids": stringList,
+			"agents": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id":               {Type: schema.TypeString, Computed: true},
+						"instance_id":      {Type: schema.TypeString, Computed: true},
+						"status":           {Type: schema.TypeString, Computed: true},
+						"online":           {Type: schema.TypeBool, Computed: true},
+						"name":             {Type: schema.TypeString, Computed: true},
+						"description":      {Type: schema.TypeString, Computed: true},
+						"hostname":         {Type: schema.TypeString, Computed: true},
+						"version":          {Type: schema.TypeString, Computed: true},
+						"agent_type":       {Type: schema.TypeString, Computed: true},
+						"os_version":       {Type: schema.TypeString, Computed: true},
+						"kernel":           {Type: schema.TypeString, Computed: true},
+						"created_at":       {Type: schema.TypeString, Computed: true},
+						"last_reported_at": {Type: schema.TypeString, Computed: true},
+						"private_ips":      stringList,
+						"public_ips":       stringList,
+						"link_local_ips":   stringList,
+						"rulesets":         stringList,
+						"tags": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"source": {Type: schema.TypeString, Computed: true},
+									"key":    {Type: schema.TypeString, Computed: true},
+									"value":  {Type: schema.TypeString, Computed: true},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceAgentsRead(resourceData *schema.ResourceData, meta interface{}) error {
+	client, err := resourceClient(resourceData, meta)
+	if err != nil {
+		return err
+	}
+
+	statuses := agentStatuses
+	if v := resourceData.Get("status").(string); v != "" {
+		statuses = []string{v}
+	}
+	// online is a shorthand for status, and unset is different from false.
+	online := ""
+	if v, ok := resourceData.GetOkExists("online"); ok {
+		online = strconv.FormatBool(v.(bool))
+		statuses = []string{"offline"}
+		if v.(bool) {
+			statuses = []string{"online"}
+		}
+	}
+
+	var hostname *regexp.Regexp
+	if v := resourceData.Get("hostname").(string); v != "" {
+		hostname = regexp.MustCompile(v)
+	}
+
+	tags := expandTags(resourceData.Get("tag").(*schema.Set).List())
+
+	var agents []*agent
+	for _, status := range statuses {
+		list, err := listAgents(client, status)
+		if err != nil {
+			return err
+		}
+		agents = append(agents, list...)
+	}
+
+	agents = filterAgents(agents, hostname, tags)
+	sort.Slice(agents, func(i, j int) bool { return agents[i].ID < agents[j].ID })
+
+	var ids []string
+	var flattened []map[string]interface{}
+	for _, v := range agents {
+		ids = append(ids, v.ID)
+		flattened = append(flattened, flattenAgent(v))
+	}
+
+	var tagKeys []string
+	for _, v := range tags {
+		tagKeys = append(tagKeys, ruleTagKey(v.Source, v.Key, v.Value))
+	}
+	sort.Strings(tagKeys)
+
+	resourceData.SetId(strconv.Itoa(hashcode.String(fmt.Sprintf("%s\n%s\n%s\n%s\n%s",
+		resourceData.Get("organization_id"), resourceData.Get("status"), online, resourceData.Get("hostname"), strings.Join(tagKeys, "\n")))))
+	resourceData.Set("ids", ids)
+	resourceData.Set("agents", flattened)
+
+	return nil
+}
+
+// filterAgents returns the agents whose hostname matches, if given, and that
+// have every one of tags.
+func filterAgents(agents []*agent, hostname *regexp.Regexp, tags []*threatstack.Tag) []*agent {
+	var ret []*agent
+	for _, a := range agents {
+		if hostname != nil && !hostname.MatchString(a.Hostname) {
+			continue
+		}
+
+		has := map[string]bool{}
+		for _, t := range a.Tags {
+			has[ruleTagKey(t.Source, t.Key, t.Value)] = true
+		}
+
+		matches := true
+		for _, t := range tags {
+			if !has[ruleTagKey(t.Source, t.Key, t.Value)] {
+				matches = false
+				break
+			}
+		}
+
+		if matches {
+			ret = append(ret, a)
+		}
+	}
+	return ret
+}
+
+func flattenAgent(a *agent) map[string]interface{} {
+	return map[string]interface{}{
+		"id":               a.ID,
+		"instance_id":      a.InstanceID,
+		"status":           a.Status,
+		"online":           a.Status == "online",
+		"name":             a.Name,
+		"description":      a.Description,
+		"hostname":         a.Hostname,
+		"version":          a.Version,
+		"agent_type":       a.AgentType,
+		"os_version":       a.OSVersion,
+		"kernel":           a.Kernel,
+		"created_at":       a.CreatedAt,
+		"last_reported_at": a.LastReportedAt,
+		"private_ips":      a.IPAddresses.Private,
+		"public_ips":       a.IPAddresses.Public,
+		"link_local_ips":   a.IPAddresses.LinkLocal,
+		"rulesets":         a.Rulesets,
+		"tags":             flattenTags(a.Tags),
+	}
+}
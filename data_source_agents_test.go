@@ -0,0 +1,104 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/jfcantu/threatstack-golang/threatstack"
+)
+
+func TestDataSourceAgentsRead(test *testing.T) {
+	client := testAPIClient(test, map[string]string{
+		"/v2/agents?status=online": `{"agents": [
+			{"id": "a2", "status": "online", "hostname": "web-2", "version": "2.3.0",
+			 "ipAddresses": {"private": ["10.0.0.2"], "public": ["203.0.113.2"], "link_local": []},
+			 "tags": [{"source": "ec2", "key": "env", "value": "prod"}], "rulesets": ["rs1"]}
+		], "token": "next"}`,
+		"/v2/agents?status=online&token=next": `{"agents": [
+			{"id": "a1", "status": "online", "hostname": "web-1",
+			 "tags": [{"source": "ec2", "key": "env", "value": "prod"}, {"source": "ec2", "key": "app", "value": "web"}]}
+		]}`,
+		"/v2/agents?status=offline": `{"agents": [
+			{"id": "a3", "status": "offline", "hostname": "db-1", "tags": [{"source": "ec2", "key": "env", "value": "prod"}]}
+		]}`,
+	})
+	meta := &providerMeta{Client: client}
+
+	for _, v := range []struct {
+		raw      map[string]interface{}
+		expected []string
+	}{
+		{map[string]interface{}{}, []string{"a1", "a2", "a3"}},
+		{map[string]interface{}{"status": "offline"}, []string{"a3"}},
+		{map[string]interface{}{"online": true}, []string{"a1", "a2"}},
+		{map[string]interface{}{"online": false}, []string{"a3"}},
+		{map[string]interface{}{"hostname": "^web-"}, []string{"a1", "a2"}},
+		{
+			map[string]interface{}{
+				"tag": []interface{}{
+					map[string]interface{}{"source": "ec2", "key": "env", "value": "prod"},
+					map[string]interface{}{"source": "ec2", "key": "app", "value": "web"},
+				},
+			},
+			[]string{"a1"},
+		},
+	} {
+		resourceData := schema.TestResourceDataRaw(test, dataSourceAgents().Schema, v.raw)
+		if err := dataSourceAgentsRead(resourceData, meta); err != nil {
+			test.Fatal(err)
+		}
+
+		var got []string
+		for _, id := range resourceData.Get("ids").([]interface{}) {
+			got = append(got, id.(string))
+		}
+		if !reflect.DeepEqual(got, v.expected) {
+			test.Errorf("Expected %v for %v, got %v", v.expected, v.raw, got)
+		}
+	}
+
+	resourceData := schema.TestResourceDataRaw(test, dataSourceAgents().Schema, map[string]interface{}{"status": "online"})
+	if err := dataSourceAgentsRead(resourceData, meta); err != nil {
+		test.Fatal(err)
+	}
+	for k, expected := range map[string]string{
+		"agents.1.id":            "a2",
+		"agents.1.online":        "true",
+		"agents.1.private_ips.0": "10.0.0.2",
+		"agents.1.public_ips.0":  "203.0.113.2",
+		"agents.1.rulesets.0":    "rs1",
+		"agents.1.tags.0.value":  "prod",
+		"agents.1.version":       "2.3.0",
+	} {
+		if got := resourceData.State().Attributes[k]; got != expected {
+			test.Errorf("%s = %q, expected %q", k, got, expected)
+		}
+	}
+}
+
+func TestDataSourceAgentsIDOrganization(test *testing.T) {
+	client := testAPIClient(test, map[string]string{
+		"/v2/agents?status=online": `{"agents": []}`,
+	})
+	meta := &providerMeta{
+		Client:  client,
+		clients: map[string]*threatstack.Client{"staging": client},
+	}
+
+	ids := map[string]bool{}
+	for _, orgID := range []string{"", "staging"} {
+		resourceData := schema.TestResourceDataRaw(test, dataSourceAgents().Schema, map[string]interface{}{
+			"organization_id": orgID,
+			"online":          true,
+		})
+		if err := dataSourceAgentsRead(resourceData, meta); err != nil {
+			test.Fatal(err)
+		}
+		ids[resourceData.Id()] = true
+	}
+
+	if len(ids) != 2 {
+		test.Error("Expected the same filters in different organizations to have different IDs")
+	}
+}
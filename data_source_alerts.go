@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+func dataSourceAlerts() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceAlertsRead,
+
+		Schema: map[string]*schema.Schema{
+			"organization_id": dataSourceOrganizationIDSchema(),
+			"rule_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"ruleset_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"severity": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntBetween(1, 3),
+			},
+			"status": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(alertStatuses, false),
+			},
+			"from": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+			},
+			"until": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+			},
+			"recent_limit": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      10,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"total": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"severity_counts": &schema.Schema{
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeInt},
+			},
+			"recent_alerts": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id":             {Type: schema.TypeString, Computed: true},
+						"title":          {Type: schema.TypeString, Computed: true},
+						"severity":       {Type: schema.TypeInt, Computed: true},
+						"created_at":     {Type: schema.TypeString, Computed: true},
+						"data_source":    {Type: schema.TypeString, Computed: true},
+						"rule_id":        {Type: schema.TypeString, Computed: true},
+						"ruleset_id":     {Type: schema.TypeString, Computed: true},
+						"agent_id":       {Type: schema.TypeString, Computed: true},
+						"is_dismissed":   {Type: schema.TypeBool, Computed: true},
+						"dismiss_reason": {Type: schema.TypeString, Computed: true},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceAlertsRead(resourceData *schema.ResourceData, meta interface{}) error {
+	client, err := resourceClient(resourceData, meta)
+	if err != nil {
+		return err
+	}
+
+	query := &alertQuery{
+		Status:    resourceData.Get("status").(string),
+		RuleID:    resourceData.Get("rule_id").(string),
+		RulesetID: resourceData.Get("ruleset_id").(string),
+		Severity:  resourceData.Get("severity").(int),
+		From:      resourceData.Get("from").(string),
+		Until:     resourceData.Get("until").(string),
+	}
+
+	alerts, err := listAlerts(client, query)
+	if err != nil {
+		return err
+	}
+
+	counts := map[string]interface{}{"1": 0, "2": 0, "3": 0}
+	for _, v := range alerts {
+		k := strconv.Itoa(v.Severity)
+		if n, ok := counts[k].(int); ok {
+			counts[k] = n + 1
+		} else {
+			counts[k] = 1
+		}
+	}
+
+	limit := resourceData.Get("recent_limit").(int)
+	recent := []map[string]interface{}{}
+	for i := 0; i < len(alerts) && i < limit; i++ {
+		v := alerts[i]
+		recent = append(recent, map[string]interface{}{
+			"id":             v.ID,
+			"title":          v.Title,
+			"severity":       v.Severity,
+			"created_at":     v.CreatedAt,
+			"data_source":    v.DataSource,
+			"rule_id":        v.RuleID,
+			"ruleset_id":     v.RulesetID,
+			"agent_id":       v.AgentID,
+			"is_dismissed":   v.IsDismissed,
+			"dismiss_reason": v.DismissReason,
+		})
+	}
+
+	resourceData.SetId(strconv.Itoa(hashcode.String(resourceData.Get("organization_id").(string) + "\x00" + fmt.Sprintf("%#v", *query))))
+	resourceData.Set("total", len(alerts))
+	resourceData.Set("severity_counts", counts)
+	resourceData.Set("recent_alerts", recent)
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func TestDataSourceAlertsRead(test *testing.T) {
+	meta := &providerMeta{
+		Client: testAPIClient(test, map[string]string{
+			"/v2/alerts?from=2020-01-01T00%3A00%3A00Z&ruleId=r1&status=active": `{"alerts": [
+				{"id": "al1", "severity": 1, "createdAt": "2020-01-02T00:00:00Z", "ruleId": "r1", "rulesetId": "rs1"},
+				{"id": "al2", "severity": 2, "createdAt": "2020-01-04T00:00:00Z", "ruleId": "r1", "rulesetId": "rs1"}
+			], "token": "next"}`,
+			"/v2/alerts?from=2020-01-01T00%3A00%3A00Z&ruleId=r1&status=active&token=next": `{"alerts": [
+				{"id": "al3", "severity": 1, "createdAt": "2020-01-03T00:00:00Z", "ruleId": "r1", "rulesetId": "rs2"}
+			]}`,
+			"/v2/alerts?from=2020-01-01T00%3A00%3A00Z&ruleId=r1&status=dismissed": `{"alerts": [
+				{"id": "al4", "severity": 1, "createdAt": "2020-01-05T00:00:00Z", "ruleId": "r1", "rulesetId": "rs1",
+				 "isDismissed": true, "dismissReason": "FALSE_POSITIVE"}
+			]}`,
+		}),
+	}
+
+	resourceData := schema.TestResourceDataRaw(test, dataSourceAlerts().Schema, map[string]interface{}{
+		"rule_id":      "r1",
+		"ruleset_id":   "rs1",
+		"from":         "2020-01-01T00:00:00Z",
+		"recent_limit": 2,
+	})
+	if err := dataSourceAlertsRead(resourceData, meta); err != nil {
+		test.Fatal(err)
+	}
+
+	for k, expected := range map[string]string{
+		"total":                          "3",
+		"severity_counts.1":              "2",
+		"severity_counts.2":              "1",
+		"severity_counts.3":              "0",
+		"recent_alerts.#":                "2",
+		"recent_alerts.0.id":             "al4",
+		"recent_alerts.0.is_dismissed":   "true",
+		"recent_alerts.0.dismiss_reason": "FALSE_POSITIVE",
+		"recent_alerts.1.id":             "al2",
+		"recent_alerts.1.severity":       "2",
+	} {
+		if got := resourceData.State().Attributes[k]; got != expected {
+			test.Errorf("%s = %q, expected %q", k, got, expected)
+		}
+	}
+}
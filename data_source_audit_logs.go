@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+func dataSourceAuditLogs() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceAuditLogsRead,
+
+		Schema: map[string]*schema.Schema{
+			"organization_id": dataSourceOrganizationIDSchema(),
+			"from": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+			},
+			"until": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+			},
+			"user_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"object_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"limit": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      100,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"total": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"entries": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id":          {Type: schema.TypeString, Computed: true},
+						"created_at":  {Type: schema.TypeString, Computed: true},
+						"user_id":     {Type: schema.TypeString, Computed: true},
+						"user_email":  {Type: schema.TypeString, Computed: true},
+						"action":      {Type: schema.TypeString, Computed: true},
+						"object_type": {Type: schema.TypeString, Computed: true},
+						"object_id":   {Type: schema.TypeString, Computed: true},
+						"object_name": {Type: schema.TypeString, Computed: true},
+						"description": {Type: schema.TypeString, Computed: true},
+						"source_ip":   {Type: schema.TypeString, Computed: true},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceAuditLogsRead(resourceData *schema.ResourceData, meta interface{}) error {
+	client, err := resourceClient(resourceData, meta)
+	if err != nil {
+		return err
+	}
+
+	query := &auditLogQuery{
+		From:     resourceData.Get("from").(string),
+		Until:    resourceData.Get("until").(string),
+		UserID:   resourceData.Get("user_id").(string),
+		ObjectID: resourceData.Get("object_id").(string),
+	}
+
+	logs, err := listAuditLogs(client, query)
+	if err != nil {
+		return err
+	}
+
+	limit := resourceData.Get("limit").(int)
+	if limit == 0 || limit > len(logs) {
+		limit = len(logs)
+	}
+
+	entries := []map[string]interface{}{}
+	for _, v := range logs[:limit] {
+		entries = append(entries, map[string]interface{}{
+			"id":          v.ID,
+			"created_at":  v.CreatedAt,
+			"user_id":     v.UserID,
+			"user_email":  v.UserEmail,
+			"action":      v.Action,
+			"object_type": v.ObjectType,
+			"object_id":   v.ObjectID,
+			"object_name": v.ObjectName,
+			"description": v.Description,
+			"source_ip":   v.SourceIP,
+		})
+	}
+
+	resourceData.SetId(strconv.Itoa(hashcode.String(resourceData.Get("organization_id").(string) + "\x00" + fmt.Sprintf("%#v", *query))))
+	resourceData.Set("total", len(logs))
+	resourceData.Set("entries", entries)
+
+	return nil
+}
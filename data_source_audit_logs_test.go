@@ -0,0 +1,43 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func TestDataSourceAuditLogsRead(test *testing.T) {
+	meta := &providerMeta{
+		Client: testAPIClient(test, map[string]string{
+			"/v2/auditlogs?from=2020-01-01T00%3A00%3A00Z&objectId=r1": `{"auditLogs": [
+				{"id": "l1", "createdAt": "2020-01-02T00:00:00Z", "userEmail": "alice@example.com", "action": "rule.update", "objectId": "r1"},
+				{"id": "l2", "createdAt": "2020-01-04T00:00:00Z", "userEmail": "bob@example.com", "action": "rule.update", "objectId": "r1"}
+			], "token": "next"}`,
+			"/v2/auditlogs?from=2020-01-01T00%3A00%3A00Z&objectId=r1&token=next": `{"auditLogs": [
+				{"id": "l3", "createdAt": "2020-01-03T00:00:00Z", "userEmail": "carol@example.com", "action": "rule.create", "objectId": "r1"}
+			]}`,
+		}),
+	}
+
+	resourceData := schema.TestResourceDataRaw(test, dataSourceAuditLogs().Schema, map[string]interface{}{
+		"from":      "2020-01-01T00:00:00Z",
+		"object_id": "r1",
+		"limit":     2,
+	})
+	if err := dataSourceAuditLogsRead(resourceData, meta); err != nil {
+		test.Fatal(err)
+	}
+
+	for k, expected := range map[string]string{
+		"total":                "3",
+		"entries.#":            "2",
+		"entries.0.id":         "l2",
+		"entries.0.user_email": "bob@example.com",
+		"entries.1.id":         "l3",
+		"entries.1.action":     "rule.create",
+	} {
+		if got := resourceData.State().Attributes[k]; got != expected {
+			test.Errorf("%s = %q, expected %q", k, got, expected)
+		}
+	}
+}
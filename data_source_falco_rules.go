@@ -0,0 +1,142 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func dataSourceFalcoRules() *schema.Resource {
+	ruleSchema := func(extra map[string]*schema.Schema) *schema.Resource {
+		s := map[string]*schema.Schema{
+			"name":        {Type: schema.TypeString, Computed: true},
+			"title":       {Type: schema.TypeString, Computed: true},
+			"description": {Type: schema.TypeString, Computed: true},
+			"severity":    {Type: schema.TypeInt, Computed: true},
+			"filter":      {Type: schema.TypeString, Computed: true},
+			"window":      {Type: schema.TypeInt, Computed: true},
+			"threshold":   {Type: schema.TypeInt, Computed: true},
+			"enabled":     {Type: schema.TypeBool, Computed: true},
+		}
+		for k, v := range extra {
+			s[k] = v
+		}
+		return &schema.Resource{Schema: s}
+	}
+
+	return &schema.Resource{
+		Read: dataSourceFalcoRulesRead,
+
+		Schema: map[string]*schema.Schema{
+			"documents": &schema.Schema{
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"host_rule": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     ruleSchema(nil),
+			},
+			"file_rule": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: ruleSchema(map[string]*schema.Schema{
+					"file_path": {
+						Type:     schema.TypeList,
+						Computed: true,
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"path":      {Type: schema.TypeString, Computed: true},
+								"recursive": {Type: schema.TypeBool, Computed: true},
+							},
+						},
+					},
+					"monitor_events": {
+						Type:     schema.TypeList,
+						Computed: true,
+						Elem:     &schema.Schema{Type: schema.TypeString},
+					},
+				}),
+			},
+			"unsupported": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"rule":   {Type: schema.TypeString, Computed: true},
+						"reason": {Type: schema.TypeString, Computed: true},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceFalcoRulesRead(resourceData *schema.ResourceData, meta interface{}) error {
+	var documents []string
+	for _, v := range resourceData.Get("documents").([]interface{}) {
+		documents = append(documents, v.(string))
+	}
+
+	conversion, err := convertFalcoRules(documents)
+	if err != nil {
+		return err
+	}
+
+	var hostRules []map[string]interface{}
+	for _, v := range conversion.HostRules {
+		hostRules = append(hostRules, map[string]interface{}{
+			"name":        v.Name,
+			"title":       v.Title,
+			"description": v.Description,
+			"severity":    v.Severity,
+			"filter":      v.Filter,
+			"window":      v.Window,
+			"threshold":   v.Threshold,
+			"enabled":     v.Enabled,
+		})
+	}
+
+	var fileRules []map[string]interface{}
+	for _, v := range conversion.FileRules {
+		var paths []map[string]interface{}
+		for _, path := range v.Paths {
+			paths = append(paths, map[string]interface{}{
+				"path":      path.Path,
+				"recursive": path.Recursive,
+			})
+		}
+
+		fileRules = append(fileRules, map[string]interface{}{
+			"name":           v.Name,
+			"title":          v.Title,
+			"description":    v.Description,
+			"severity":       v.Severity,
+			"filter":         v.Filter,
+			"window":         v.Window,
+			"threshold":      v.Threshold,
+			"enabled":        v.Enabled,
+			"file_path":      paths,
+			"monitor_events": v.MonitorEvents,
+		})
+	}
+
+	var unsupported []map[string]interface{}
+	for _, v := range conversion.Unsupported {
+		unsupported = append(unsupported, map[string]interface{}{
+			"rule":   v.Rule,
+			"reason": v.Reason,
+		})
+	}
+
+	resourceData.SetId(strconv.Itoa(hashcode.String(strings.Join(documents, "\n---\n"))))
+	resourceData.Set("host_rule", hostRules)
+	resourceData.Set("file_rule", fileRules)
+	resourceData.Set("unsupported", unsupported)
+
+	return nil
+}
@@ -0,0 +1,439 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/jfcantu/threatstack-golang/threatstack"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// rulePackRule is a rule as authored in a rule pack YAML file. Field names
+// match the arguments of the rule resources.
+type rulePackRule struct {
+	Type            string             `yaml:"type"`
+	Name            string             `yaml:"name"`
+	Title           string             `yaml:"title"`
+	Description     string             `yaml:"description"`
+	Severity        int                `yaml:"severity"`
+	AggregateFields []string           `yaml:"aggregate_fields"`
+	Filter          string             `yaml:"filter"`
+	Window          int                `yaml:"window"`
+	Threshold       int                `yaml:"threshold"`
+	Suppressions    []string           `yaml:"suppressions"`
+	Enabled         *bool              `yaml:"enabled"`
+	IncludeTags     []rulePackTag      `yaml:"include_tag"`
+	ExcludeTags     []rulePackTag      `yaml:"exclude_tag"`
+	FilePaths       []rulePackFilePath `yaml:"file_path"`
+	IgnoreFiles     []string           `yaml:"ignore_files"`
+	MonitorEvents   []string           `yaml:"monitor_events"`
+}
+
+type rulePackTag struct {
+	Source string `yaml:"source"`
+	Key    string `yaml:"key"`
+	Value  string `yaml:"value"`
+}
+
+type rulePackFilePath struct {
+	Path      string `yaml:"path"`
+	Recursive bool   `yaml:"recursive"`
+}
+
+func dataSourceRulePack() *schema.Resource {
+	tagSchema := &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"source": {Type: schema.TypeString, Computed: true},
+				"key":    {Type: schema.TypeString, Computed: true},
+				"value":  {Type: schema.TypeString, Computed: true},
+			},
+		},
+	}
+
+	ruleSchema := func(extra map[string]*schema.Schema) *schema.Resource {
+		s := map[string]*schema.Schema{
+			"key":              {Type: schema.TypeString, Computed: true},
+			"name":             {Type: schema.TypeString, Computed: true},
+			"title":            {Type: schema.TypeString, Computed: true},
+			"description":      {Type: schema.TypeString, Computed: true},
+			"severity":         {Type: schema.TypeInt, Computed: true},
+			"aggregate_fields": {Type: schema.TypeList, Computed: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			"filter":           {Type: schema.TypeString, Computed: true},
+			"window":           {Type: schema.TypeInt, Computed: true},
+			"threshold":        {Type: schema.TypeInt, Computed: true},
+			"suppressions":     {Type: schema.TypeList, Computed: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			"enabled":          {Type: schema.TypeBool, Computed: true},
+			"include_tag":      tagSchema,
+			"exclude_tag":      tagSchema,
+		}
+		for k, v := range extra {
+			s[k] = v
+		}
+		return &schema.Resource{Schema: s}
+	}
+
+	return &schema.Resource{
+		Read: dataSourceRulePackRead,
+
+		Schema: map[string]*schema.Schema{
+			"path": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"host_rule": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     ruleSchema(nil),
+			},
+			"file_rule": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: ruleSchema(map[string]*schema.Schema{
+					"file_path": {
+						Type:     schema.TypeList,
+						Computed: true,
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"path":      {Type: schema.TypeString, Computed: true},
+								"recursive": {Type: schema.TypeBool, Computed: true},
+							},
+						},
+					},
+					"ignore_files":   {Type: schema.TypeList, Computed: true, Elem: &schema.Schema{Type: schema.TypeString}},
+					"monitor_events": {Type: schema.TypeList, Computed: true, Elem: &schema.Schema{Type: schema.TypeString}},
+				}),
+			},
+		},
+	}
+}
+
+func dataSourceRulePackRead(resourceData *schema.ResourceData, meta interface{}) error {
+	dir := resourceData.Get("path").(string)
+
+	entries, err := readRulePack(dir)
+	if err != nil {
+		return err
+	}
+
+	hostRules := []map[string]interface{}{}
+	fileRules := []map[string]interface{}{}
+	var contents []string
+
+	for _, v := range entries {
+		contents = append(contents, string(v.Content))
+
+		flattened := v.Rule.flatten(v.Key)
+		if v.Rule.Type == "host" {
+			hostRules = append(hostRules, flattened)
+		} else {
+			fileRules = append(fileRules, flattened)
+		}
+	}
+
+	resourceData.SetId(strconv.Itoa(hashcode.String(dir + "\n" + strings.Join(contents, "\n---\n"))))
+	resourceData.Set("host_rule", hostRules)
+	resourceData.Set("file_rule", fileRules)
+
+	return nil
+}
+
+// rulePackEntry is a single file of a rule pack.
+type rulePackEntry struct {
+	Key     string
+	File    string
+	Content []byte
+	Rule    *rulePackRule
+}
+
+// readRulePack reads and validates every rule file in a directory, in file
+// name order.
+func readRulePack(dir string) ([]*rulePackEntry, error) {
+	var files []string
+	for _, pattern := range []string{"*.yaml", "*.yml"} {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+
+	var ret []*rulePackEntry
+	keys := map[string]string{}
+
+	for _, file := range files {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		key := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		if other, ok := keys[key]; ok {
+			return nil, fmt.Errorf("Rule pack files %s and %s have the same key %q", other, file, key)
+		}
+		keys[key] = file
+
+		rule, err := parseRulePackRule(content)
+		if err != nil {
+			return nil, fmt.Errorf("Error in rule pack file %s: %s", file, err.Error())
+		}
+
+		ret = append(ret, &rulePackEntry{
+			Key:     key,
+			File:    file,
+			Content: content,
+			Rule:    rule,
+		})
+	}
+
+	return ret, nil
+}
+
+// parseRulePackRule parses a rule pack document and validates it against the
+// schema of the matching rule resource.
+func parseRulePackRule(content []byte) (*rulePackRule, error) {
+	rule := new(rulePackRule)
+	if err := yaml.UnmarshalStrict(content, rule); err != nil {
+		return nil, err
+	}
+
+	var resource *schema.Resource
+	switch rule.Type {
+	case "host":
+		if len(rule.FilePaths) > 0 || len(rule.IgnoreFiles) > 0 || len(rule.MonitorEvents) > 0 {
+			return nil, fmt.Errorf("file_path, ignore_files and monitor_events are only valid for file rules")
+		}
+		resource = resourceHostRule()
+	case "file":
+		resource = resourceFileRule()
+	default:
+		return nil, fmt.Errorf("type must be \"host\" or \"file\", got %q", rule.Type)
+	}
+
+	warnings, errs := resource.Validate(terraform.NewResourceConfigRaw(rule.config()))
+	w, e := rule.validateValues()
+	warnings, errs = append(warnings, w...), append(errs, e...)
+	for _, v := range warnings {
+		errs = append(errs, fmt.Errorf("%s", v))
+	}
+	if len(errs) > 0 {
+		var messages []string
+		for _, v := range errs {
+			messages = append(messages, v.Error())
+		}
+		return nil, fmt.Errorf("%s", strings.Join(messages, "; "))
+	}
+
+	return rule, nil
+}
+
+// validateValues applies the checks that are stricter than the rule
+// resources', which accept any window, threshold and aggregate field so that
+// existing configurations keep working. Aggregate fields are only checked
+// for host rules, whose list is known.
+func (rule *rulePackRule) validateValues() ([]string, []error) {
+	var warnings []string
+	var errs []error
+	check := func(f schema.SchemaValidateFunc, v interface{}, key string) {
+		w, e := f(v, key)
+		warnings, errs = append(warnings, w...), append(errs, e...)
+	}
+
+	if rule.Window != 0 {
+		check(validateRuleWindow(), rule.Window, "window")
+	}
+	if rule.Threshold != 0 {
+		check(validateRuleThreshold(), rule.Threshold, "threshold")
+	}
+	if rule.Type == "host" {
+		for i, v := range rule.AggregateFields {
+			check(validateHostRuleAggregateFields(), v, fmt.Sprintf("aggregate_fields.%d", i))
+		}
+	}
+
+	return warnings, errs
+}
+
+// config returns the rule as resource configuration, for validation. Rule
+// packs don't know which ruleset their rules will be added to, so a
+// placeholder is used.
+func (rule *rulePackRule) config() map[string]interface{} {
+	raw := map[string]interface{}{
+		"ruleset": "rule_pack",
+	}
+
+	set := func(key string, value interface{}, present bool) {
+		if present {
+			raw[key] = value
+		}
+	}
+
+	set("name", rule.Name, rule.Name != "")
+	set("title", rule.Title, rule.Title != "")
+	set("description", rule.Description, rule.Description != "")
+	set("severity", rule.Severity, rule.Severity != 0)
+	set("aggregate_fields", stringsToInterfaces(rule.AggregateFields), rule.AggregateFields != nil)
+	set("filter", rule.Filter, rule.Filter != "")
+	set("window", rule.Window, rule.Window != 0)
+	set("threshold", rule.Threshold, rule.Threshold != 0)
+	set("suppressions", stringsToInterfaces(rule.Suppressions), rule.Suppressions != nil)
+	if rule.Enabled != nil {
+		raw["enabled"] = *rule.Enabled
+	}
+	set("include_tag", rulePackTagsConfig(rule.IncludeTags), rule.IncludeTags != nil)
+	set("exclude_tag", rulePackTagsConfig(rule.ExcludeTags), rule.ExcludeTags != nil)
+	set("ignore_files", stringsToInterfaces(rule.IgnoreFiles), rule.IgnoreFiles != nil)
+	set("monitor_events", stringsToInterfaces(rule.MonitorEvents), rule.MonitorEvents != nil)
+
+	if rule.FilePaths != nil {
+		var paths []interface{}
+		for _, v := range rule.FilePaths {
+			paths = append(paths, map[string]interface{}{
+				"path":      v.Path,
+				"recursive": v.Recursive,
+			})
+		}
+		raw["file_path"] = paths
+	}
+
+	return raw
+}
+
+// flatten returns the rule with defaults applied and lists sorted, so that
+// reordering a file doesn't change the data source.
+func (rule *rulePackRule) flatten(key string) map[string]interface{} {
+	enabled := true
+	if rule.Enabled != nil {
+		enabled = *rule.Enabled
+	}
+
+	ret := map[string]interface{}{
+		"key":              key,
+		"name":             rule.Name,
+		"title":            rule.Title,
+		"description":      rule.Description,
+		"severity":         rule.Severity,
+		"aggregate_fields": sortedStrings(rule.AggregateFields),
+		"filter":           rule.Filter,
+		"window":           rule.Window,
+		"threshold":        rule.Threshold,
+		"suppressions":     sortedStrings(rule.Suppressions),
+		"enabled":          enabled,
+		"include_tag":      rulePackTagsConfig(sortRulePackTags(rule.IncludeTags)),
+		"exclude_tag":      rulePackTagsConfig(sortRulePackTags(rule.ExcludeTags)),
+	}
+
+	if rule.Type == "file" {
+		paths := append([]rulePackFilePath{}, rule.FilePaths...)
+		sort.Slice(paths, func(i, j int) bool { return paths[i].Path < paths[j].Path })
+
+		var flattened []map[string]interface{}
+		for _, v := range paths {
+			flattened = append(flattened, map[string]interface{}{
+				"path":      v.Path,
+				"recursive": v.Recursive,
+			})
+		}
+
+		ret["file_path"] = flattened
+		ret["ignore_files"] = sortedStrings(rule.IgnoreFiles)
+		ret["monitor_events"] = sortedStrings(rule.MonitorEvents)
+	}
+
+	return ret
+}
+
+// threatstackRule converts the rule to its API form.
+func (rule *rulePackRule) threatstackRule() threatstack.Rule {
+	enabled := true
+	if rule.Enabled != nil {
+		enabled = *rule.Enabled
+	}
+
+	tags := threatstack.NewTagSet()
+	for _, v := range rule.IncludeTags {
+		tags.Include = append(tags.Include, &threatstack.Tag{Source: v.Source, Key: v.Key, Value: v.Value})
+	}
+	for _, v := range rule.ExcludeTags {
+		tags.Exclude = append(tags.Exclude, &threatstack.Tag{Source: v.Source, Key: v.Key, Value: v.Value})
+	}
+
+	if rule.Type == "file" {
+		paths := []*threatstack.FilePath{}
+		for _, v := range rule.FilePaths {
+			paths = append(paths, &threatstack.FilePath{Path: v.Path, Recursive: v.Recursive})
+		}
+
+		return &threatstack.FileRule{
+			Type:            "File",
+			Name:            rule.Name,
+			Tags:            tags,
+			Title:           rule.Title,
+			Description:     rule.Description,
+			Severity:        rule.Severity,
+			AggregateFields: rule.AggregateFields,
+			Filter:          rule.Filter,
+			Window:          rule.Window,
+			Threshold:       rule.Threshold,
+			Suppressions:    rule.Suppressions,
+			Paths:           paths,
+			IgnoreFiles:     rule.IgnoreFiles,
+			MonitorEvents:   rule.MonitorEvents,
+			Enabled:         enabled,
+		}
+	}
+
+	return &threatstack.HostRule{
+		Type:            "Host",
+		Name:            rule.Name,
+		Tags:            tags,
+		Title:           rule.Title,
+		Description:     rule.Description,
+		Severity:        rule.Severity,
+		AggregateFields: rule.AggregateFields,
+		Filter:          rule.Filter,
+		Window:          rule.Window,
+		Threshold:       rule.Threshold,
+		Suppressions:    rule.Suppressions,
+		Enabled:         enabled,
+	}
+}
+
+func sortRulePackTags(tags []rulePackTag) []rulePackTag {
+	ret := append([]rulePackTag{}, tags...)
+	sort.Slice(ret, func(i, j int) bool {
+		return fmt.Sprintf("%s\x00%s\x00%s", ret[i].Source, ret[i].Key, ret[i].Value) <
+			fmt.Sprintf("%s\x00%s\x00%s", ret[j].Source, ret[j].Key, ret[j].Value)
+	})
+	return ret
+}
+
+func rulePackTagsConfig(tags []rulePackTag) []interface{} {
+	ret := []interface{}{}
+	for _, v := range tags {
+		ret = append(ret, map[string]interface{}{
+			"source": v.Source,
+			"key":    v.Key,
+			"value":  v.Value,
+		})
+	}
+	return ret
+}
+
+func stringsToInterfaces(list []string) []interface{} {
+	ret := []interface{}{}
+	for _, v := range list {
+		ret = append(ret, v)
+	}
+	return ret
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func TestDataSourceRulePackRead(test *testing.T) {
+	dir, err := ioutil.TempDir("", "rulepack")
+	if err != nil {
+		test.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"new_user.yaml": `
+type: host
+name: "Host: New User Added"
+title: "Host: New User Added"
+severity: 3
+filter: event_type = "host" and sigid = "5902"
+window: 86400
+threshold: 1
+suppressions: ['user = "chef"', 'user = "ansible"']
+include_tag:
+  - source: ec2
+    key: environment
+    value: production
+`,
+		"secret_files.yml": `
+type: file
+name: "File: Secret File Opens"
+title: "File: Secret File Opens"
+severity: 1
+filter: user != "ts-user"
+window: 3600
+threshold: 1
+file_path:
+  - path: /home/ubuntu/.aws
+    recursive: true
+monitor_events: [open]
+enabled: false
+`,
+		"README.md": "not a rule",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			test.Fatal(err)
+		}
+	}
+
+	resourceData := schema.TestResourceDataRaw(test, dataSourceRulePack().Schema, map[string]interface{}{
+		"path": dir,
+	})
+	if err := dataSourceRulePackRead(resourceData, nil); err != nil {
+		test.Fatal(err)
+	}
+
+	for k, expected := range map[string]string{
+		"host_rule.#":                     "1",
+		"host_rule.0.key":                 "new_user",
+		"host_rule.0.enabled":             "true",
+		"host_rule.0.suppressions.0":      `user = "ansible"`,
+		"host_rule.0.include_tag.0.value": "production",
+		"file_rule.#":                     "1",
+		"file_rule.0.key":                 "secret_files",
+		"file_rule.0.enabled":             "false",
+		"file_rule.0.file_path.0.path":    "/home/ubuntu/.aws",
+		"file_rule.0.monitor_events.0":    "open",
+	} {
+		if got := resourceData.State().Attributes[k]; got != expected {
+			test.Errorf("%s = %q, expected %q", k, got, expected)
+		}
+	}
+}
+
+func TestParseRulePackRuleInvalid(test *testing.T) {
+	for _, v := range []struct {
+		content  string
+		expected string
+	}{
+		{"type: host\nname: a\ntitle: a\nseverity: 1\nfilter: a\nwindow: 60\nthreshold: 1\n", "window"},
+		{"type: host\nname: a\ntitle: a\nseverity: 1\nfilter: a\nwindow: 3600\nthreshold: 1\naggregate_fields: [bogus]\n", "aggregate_fields"},
+		{"type: host\nname: a\ntitle: a\nseverity: 1\nfilter: a\nwindow: 3600\nthreshold: 3\n", "threshold"},
+		{"type: host\ntitle: a\nseverity: 1\nfilter: a\nwindow: 3600\nthreshold: 1\n", `"name": required field is not set`},
+		{"type: file\nname: a\ntitle: a\nseverity: 1\nfilter: a\nwindow: 3600\nthreshold: 1\n", `"file_path": required field is not set`},
+		{"type: host\nname: a\nbogus: 1\n", "field bogus not found"},
+		{"type: cloudtrail\nname: a\n", "type must be"},
+	} {
+		_, err := parseRulePackRule([]byte(v.content))
+		if err == nil || !strings.Contains(err.Error(), v.expected) {
+			test.Errorf("Expected error containing %q, got %v", v.expected, err)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func dataSourceSigmaRule() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceSigmaRuleRead,
+
+		Schema: map[string]*schema.Schema{
+			"content": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"title": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"description": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"severity": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"filter": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceSigmaRuleRead(resourceData *schema.ResourceData, meta interface{}) error {
+	content := resourceData.Get("content").(string)
+
+	rule, err := compileSigmaRule(content)
+	if err != nil {
+		return err
+	}
+
+	resourceData.SetId(strconv.Itoa(hashcode.String(content)))
+	resourceData.Set("title", rule.Title)
+	resourceData.Set("description", rule.Description)
+	resourceData.Set("severity", rule.Severity)
+	resourceData.Set("filter", rule.Filter)
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/jfcantu/threatstack-golang/threatstack"
+)
+
+func dataSourceTags() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceTagsRead,
+
+		Schema: map[string]*schema.Schema{
+			"organization_id": dataSourceOrganizationIDSchema(),
+			"source": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"key": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"tags": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"source": {Type: schema.TypeString, Computed: true},
+						"key":    {Type: schema.TypeString, Computed: true},
+						"value":  {Type: schema.TypeString, Computed: true},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceTagsRead(resourceData *schema.ResourceData, meta interface{}) error {
+	source := resourceData.Get("source").(string)
+	key := resourceData.Get("key").(string)
+	orgID := resourceData.Get("organization_id").(string)
+
+	known, err := meta.(*providerMeta).HostTags(orgID)
+	if err != nil {
+		return err
+	}
+
+	resourceData.SetId(strconv.Itoa(hashcode.String(orgID + "\x00" + source + "\x00" + key)))
+	resourceData.Set("tags", flattenTags(filterTags(known, source, key)))
+
+	return nil
+}
+
+// filterTags returns the tags matching source and key. An empty filter
+// matches everything.
+func filterTags(tags []*threatstack.Tag, source, key string) []*threatstack.Tag {
+	var ret []*threatstack.Tag
+	for _, v := range tags {
+		if (source == "" || v.Source == source) && (key == "" || v.Key == key) {
+			ret = append(ret, v)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/jfcantu/threatstack-golang/threatstack"
+)
+
+func TestDataSourceTagsRead(test *testing.T) {
+	meta := &providerMeta{}
+	meta.hostTags = map[string]*hostTagsResult{"": {tags: testKnownTags}}
+
+	for _, v := range []struct {
+		raw      map[string]interface{}
+		expected []string
+	}{
+		{
+			map[string]interface{}{},
+			[]string{"ec2:environment=production", "ec2:environment=staging", "ec2:team=payments"},
+		},
+		{
+			map[string]interface{}{"source": "ec2", "key": "environment"},
+			[]string{"ec2:environment=production", "ec2:environment=staging"},
+		},
+		{
+			map[string]interface{}{"source": "gce"},
+			nil,
+		},
+	} {
+		resourceData := schema.TestResourceDataRaw(test, dataSourceTags().Schema, v.raw)
+		if err := dataSourceTagsRead(resourceData, meta); err != nil {
+			test.Fatal(err)
+		}
+
+		var got []string
+		for _, tag := range expandTags(resourceData.Get("tags").([]interface{})) {
+			got = append(got, formatTag(tag))
+		}
+		if len(got) != len(v.expected) {
+			test.Errorf("Expected %v for %v, got %v", v.expected, v.raw, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != v.expected[i] {
+				test.Errorf("Expected %v for %v, got %v", v.expected, v.raw, got)
+				break
+			}
+		}
+	}
+}
+
+func TestDataSourceTagsIDOrganization(test *testing.T) {
+	meta := &providerMeta{
+		clients: map[string]*threatstack.Client{"staging": {}},
+	}
+	meta.hostTags = map[string]*hostTagsResult{"": {tags: testKnownTags}, "staging": {tags: testKnownTags}}
+
+	ids := map[string]bool{}
+	for _, orgID := range []string{"", "staging"} {
+		resourceData := schema.TestResourceDataRaw(test, dataSourceTags().Schema, map[string]interface{}{
+			"organization_id": orgID,
+			"source":          "ec2",
+		})
+		if err := dataSourceTagsRead(resourceData, meta); err != nil {
+			test.Fatal(err)
+		}
+		ids[resourceData.Id()] = true
+	}
+
+	if len(ids) != 2 {
+		test.Error("Expected the same filters in different organizations to have different IDs")
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+var cvePattern = regexp.MustCompile(`^CVE-\d{4}-\d{4,}$`)
+
+func dataSourceVulnerabilities() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceVulnerabilitiesRead,
+
+		Schema: map[string]*schema.Schema{
+			"organization_id": dataSourceOrganizationIDSchema(),
+			"cve_id": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringMatch(cvePattern, "must be a CVE ID, e.g. CVE-2020-1234"),
+			},
+			"severity": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(vulnerabilitySeverities, false),
+			},
+			"package": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"agent_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"include_suppressed": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"cve_ids": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"vulnerabilities": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"cve_id":           {Type: schema.TypeString, Computed: true},
+						"severity":         {Type: schema.TypeString, Computed: true},
+						"reported_package": {Type: schema.TypeString, Computed: true},
+						"system_package":   {Type: schema.TypeString, Computed: true},
+						"vector_type":      {Type: schema.TypeString, Computed: true},
+						"is_suppressed":    {Type: schema.TypeBool, Computed: true},
+						"agent_ids": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceVulnerabilitiesRead(resourceData *schema.ResourceData, meta interface{}) error {
+	client, err := resourceClient(resourceData, meta)
+	if err != nil {
+		return err
+	}
+
+	query := &vulnerabilityQuery{
+		CVENumber: resourceData.Get("cve_id").(string),
+		Severity:  resourceData.Get("severity").(string),
+		Package:   resourceData.Get("package").(string),
+		AgentID:   resourceData.Get("agent_id").(string),
+	}
+	includeSuppressed := resourceData.Get("include_suppressed").(bool)
+
+	vulns, err := listVulnerabilities(client, query)
+	if err != nil {
+		return err
+	}
+
+	cveIDs := []string{}
+	seen := map[string]bool{}
+	flattened := []map[string]interface{}{}
+	for _, v := range vulns {
+		if v.IsSuppressed && !includeSuppressed {
+			continue
+		}
+
+		if !seen[v.CVENumber] {
+			seen[v.CVENumber] = true
+			cveIDs = append(cveIDs, v.CVENumber)
+		}
+
+		flattened = append(flattened, map[string]interface{}{
+			"cve_id":           v.CVENumber,
+			"severity":         v.Severity,
+			"reported_package": v.ReportedPackage,
+			"system_package":   v.SystemPackage,
+			"vector_type":      v.VectorType,
+			"is_suppressed":    v.IsSuppressed,
+			"agent_ids":        sortedStrings(v.AgentIDs),
+		})
+	}
+
+	resourceData.SetId(strconv.Itoa(hashcode.String(resourceData.Get("organization_id").(string) + "\x00" + fmt.Sprintf("%#v %t", *query, includeSuppressed))))
+	resourceData.Set("cve_ids", cveIDs)
+	resourceData.Set("vulnerabilities", flattened)
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func TestDataSourceVulnerabilitiesRead(test *testing.T) {
+	meta := &providerMeta{
+		Client: testAPIClient(test, map[string]string{
+			"/v2/vulnerabilities?packageName=openssl&severity=high": `{"cves": [
+				{"cveNumber": "CVE-2016-2107", "reportedPackage": "openssl", "severity": "high", "agentIds": ["a2", "a1"]},
+				{"cveNumber": "CVE-2014-0160", "reportedPackage": "openssl", "severity": "high", "isSuppressed": true}
+			], "token": "next"}`,
+			"/v2/vulnerabilities?packageName=openssl&severity=high&token=next": `{"cves": [
+				{"cveNumber": "CVE-2016-2107", "reportedPackage": "libssl", "severity": "high"}
+			]}`,
+		}),
+	}
+
+	for _, v := range []struct {
+		includeSuppressed bool
+		expected          map[string]string
+	}{
+		{false, map[string]string{
+			"cve_ids.#":                          "1",
+			"cve_ids.0":                          "CVE-2016-2107",
+			"vulnerabilities.#":                  "2",
+			"vulnerabilities.0.reported_package": "libssl",
+			"vulnerabilities.1.agent_ids.0":      "a1",
+		}},
+		{true, map[string]string{
+			"cve_ids.#":                       "2",
+			"cve_ids.0":                       "CVE-2014-0160",
+			"vulnerabilities.#":               "3",
+			"vulnerabilities.0.is_suppressed": "true",
+		}},
+	} {
+		resourceData := schema.TestResourceDataRaw(test, dataSourceVulnerabilities().Schema, map[string]interface{}{
+			"severity":           "high",
+			"package":            "openssl",
+			"include_suppressed": v.includeSuppressed,
+		})
+		if err := dataSourceVulnerabilitiesRead(resourceData, meta); err != nil {
+			test.Fatal(err)
+		}
+
+		for k, expected := range v.expected {
+			if got := resourceData.State().Attributes[k]; got != expected {
+				test.Errorf("With include_suppressed = %t, %s = %q, expected %q", v.includeSuppressed, k, got, expected)
+			}
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/jfcantu/threatstack-golang/threatstack"
+)
+
+// ruleTagSchema is the schema of the include_tag and exclude_tag blocks of
+// rules and the provider's default tags.
+func ruleTagSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeSet,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"source": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"key": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"value": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+			},
+		},
+	}
+}
+
+// ruleTagAllSchema is the schema of include_tag_all and exclude_tag_all, the
+// tags that are actually sent for a rule: its own tags merged with the
+// provider's default tags.
+func ruleTagAllSchema() *schema.Schema {
+	s := ruleTagSchema()
+	s.Optional = false
+	s.Computed = true
+	return s
+}
+
+func ruleTagKey(source, key, value string) string {
+	return fmt.Sprintf("%s\x00%s\x00%s", source, key, value)
+}
+
+// expandTags converts include_tag or exclude_tag style set elements to tags.
+func expandTags(list []interface{}) []*threatstack.Tag {
+	var ret []*threatstack.Tag
+	for _, v := range list {
+		ret = append(ret, &threatstack.Tag{
+			Source: v.(map[string]interface{})["source"].(string),
+			Key:    v.(map[string]interface{})["key"].(string),
+			Value:  v.(map[string]interface{})["value"].(string),
+		})
+	}
+	return ret
+}
+
+func flattenTags(tags []*threatstack.Tag) []interface{} {
+	ret := []interface{}{}
+	for _, v := range tags {
+		ret = append(ret, map[string]interface{}{
+			"source": v.Source,
+			"key":    v.Key,
+			"value":  v.Value,
+		})
+	}
+	return ret
+}
+
+// mergeTags returns tags followed by any defaults that aren't already in it.
+func mergeTags(tags []*threatstack.Tag, defaults []*threatstack.Tag) []*threatstack.Tag {
+	seen := map[string]bool{}
+	ret := append([]*threatstack.Tag{}, tags...)
+	for _, v := range tags {
+		seen[ruleTagKey(v.Source, v.Key, v.Value)] = true
+	}
+	for _, v := range defaults {
+		if !seen[ruleTagKey(v.Source, v.Key, v.Value)] {
+			ret = append(ret, v)
+		}
+	}
+	return ret
+}
+
+// providerDefaultTags returns the provider's default tags, or an empty set if
+// there are none or the rule opted out with ignore_default_tags.
+func providerDefaultTags(ignore bool, meta interface{}) *threatstack.TagSet {
+	if m, ok := meta.(*providerMeta); ok && m.DefaultTags != nil && !ignore {
+		return m.DefaultTags
+	}
+	return threatstack.NewTagSet()
+}
+
+// customizeDiffDefaultTags plans include_tag_all and exclude_tag_all, so that
+// changes to the provider's default tags show up in the diff of every rule.
+func customizeDiffDefaultTags(d *schema.ResourceDiff, meta interface{}) error {
+	defaults := providerDefaultTags(d.Get("ignore_default_tags").(bool), meta)
+
+	for _, v := range []struct {
+		key      string
+		defaults []*threatstack.Tag
+	}{
+		{"include_tag", defaults.Include},
+		{"exclude_tag", defaults.Exclude},
+	} {
+		if !d.NewValueKnown(v.key) {
+			if err := d.SetNewComputed(v.key + "_all"); err != nil {
+				return err
+			}
+			continue
+		}
+
+		tags := mergeTags(expandTags(d.Get(v.key).(*schema.Set).List()), v.defaults)
+		if err := d.SetNew(v.key+"_all", flattenTags(tags)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// expandRuleTags returns the tags to send for a rule, including the
+// provider's default tags.
+func expandRuleTags(resourceData *schema.ResourceData) *threatstack.TagSet {
+	tags := threatstack.NewTagSet()
+	tags.Include = expandTags(resourceData.Get("include_tag_all").(*schema.Set).List())
+	tags.Exclude = expandTags(resourceData.Get("exclude_tag_all").(*schema.Set).List())
+	return tags
+}
+
+// setRuleTags sets a rule's tags from the API. Default tags are left out of
+// include_tag and exclude_tag unless the rule also configures them itself.
+func setRuleTags(resourceData *schema.ResourceData, tags *threatstack.TagSet, meta interface{}) {
+	if tags == nil {
+		tags = threatstack.NewTagSet()
+	}
+	defaults := providerDefaultTags(resourceData.Get("ignore_default_tags").(bool), meta)
+
+	for _, v := range []struct {
+		key      string
+		tags     []*threatstack.Tag
+		defaults []*threatstack.Tag
+	}{
+		{"include_tag", tags.Include, defaults.Include},
+		{"exclude_tag", tags.Exclude, defaults.Exclude},
+	} {
+		configured := map[string]bool{}
+		for _, t := range expandTags(resourceData.Get(v.key).(*schema.Set).List()) {
+			configured[ruleTagKey(t.Source, t.Key, t.Value)] = true
+		}
+		isDefault := map[string]bool{}
+		for _, t := range v.defaults {
+			isDefault[ruleTagKey(t.Source, t.Key, t.Value)] = true
+		}
+
+		var own []*threatstack.Tag
+		for _, t := range v.tags {
+			k := ruleTagKey(t.Source, t.Key, t.Value)
+			if configured[k] || !isDefault[k] {
+				own = append(own, t)
+			}
+		}
+
+		resourceData.Set(v.key, flattenTags(own))
+		resourceData.Set(v.key+"_all", flattenTags(v.tags))
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/jfcantu/threatstack-golang/threatstack"
+)
+
+func testDefaultTagsMeta() *providerMeta {
+	return &providerMeta{
+		DefaultTags: &threatstack.TagSet{
+			Exclude: []*threatstack.Tag{{Source: "ec2", Key: "environment", Value: "sandbox"}},
+		},
+	}
+}
+
+func testDefaultTagsConfig() map[string]interface{} {
+	return map[string]interface{}{
+		"name":      "rule",
+		"title":     "rule",
+		"ruleset":   "ruleset",
+		"severity":  1,
+		"filter":    `event_type = "host" and sigid = "5902"`,
+		"window":    86400,
+		"threshold": 1,
+		"exclude_tag": []interface{}{
+			map[string]interface{}{"source": "ec2", "key": "team", "value": "qa"},
+		},
+	}
+}
+
+func TestCustomizeDiffDefaultTags(test *testing.T) {
+	for _, v := range []struct {
+		ignore   bool
+		expected string
+	}{
+		{false, "2"},
+		{true, "1"},
+	} {
+		raw := testDefaultTagsConfig()
+		raw["ignore_default_tags"] = v.ignore
+
+		diff, err := resourceHostRule().Diff(nil, terraform.NewResourceConfigRaw(raw), testDefaultTagsMeta())
+		if err != nil {
+			test.Fatal(err)
+		}
+
+		if got := diff.Attributes["exclude_tag_all.#"].New; got != v.expected {
+			test.Errorf("With ignore_default_tags = %t, expected %s exclude_tag_all, got %s", v.ignore, v.expected, got)
+		}
+		if got := diff.Attributes["exclude_tag.#"].New; got != "1" {
+			test.Errorf("With ignore_default_tags = %t, expected 1 exclude_tag, got %s", v.ignore, got)
+		}
+	}
+}
+
+func TestSetRuleTags(test *testing.T) {
+	resourceData := schema.TestResourceDataRaw(test, resourceHostRule().Schema, testDefaultTagsConfig())
+
+	setRuleTags(resourceData, &threatstack.TagSet{
+		Exclude: []*threatstack.Tag{
+			{Source: "ec2", Key: "team", Value: "qa"},
+			{Source: "ec2", Key: "environment", Value: "sandbox"},
+			{Source: "ec2", Key: "owner", Value: "console"},
+		},
+	}, testDefaultTagsMeta())
+
+	// The default tag is only in exclude_tag_all, while a tag added outside
+	// of Terraform shows up in exclude_tag so that it's reported as a diff.
+	if got := resourceData.Get("exclude_tag").(*schema.Set).Len(); got != 2 {
+		test.Errorf("Expected 2 exclude_tag, got %d", got)
+	}
+	if got := resourceData.Get("exclude_tag_all").(*schema.Set).Len(); got != 3 {
+		test.Errorf("Expected 3 exclude_tag_all, got %d", got)
+	}
+}
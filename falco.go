@@ -0,0 +1,795 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jfcantu/threatstack-golang/threatstack"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Falco rules have no equivalent of a window or threshold, so every
+// converted rule alerts on the first matching event.
+const (
+	falcoRuleWindow    = 3600
+	falcoRuleThreshold = 1
+)
+
+type falcoItem struct {
+	Rule       string            `yaml:"rule"`
+	Macro      string            `yaml:"macro"`
+	List       string            `yaml:"list"`
+	Desc       string            `yaml:"desc"`
+	Condition  string            `yaml:"condition"`
+	Priority   string            `yaml:"priority"`
+	Source     string            `yaml:"source"`
+	Enabled    *bool             `yaml:"enabled"`
+	Items      []interface{}     `yaml:"items"`
+	Append     bool              `yaml:"append"`
+	Exceptions []*falcoException `yaml:"exceptions"`
+}
+
+// falcoException is an exception of a Falco rule. Fields is either a list of
+// fields, in which case each of Values is a tuple with one value per field,
+// or a single field, in which case Values are values of that field. Comps
+// are the matching operators, "=" or "in" respectively by default.
+type falcoException struct {
+	Name   string        `yaml:"name"`
+	Fields interface{}   `yaml:"fields"`
+	Comps  interface{}   `yaml:"comps"`
+	Values []interface{} `yaml:"values"`
+}
+
+// falcoConversion is the result of converting a set of Falco rules files.
+type falcoConversion struct {
+	HostRules   []*threatstack.HostRule
+	FileRules   []*threatstack.FileRule
+	Unsupported []*falcoUnsupported
+}
+
+// falcoUnsupported is a Falco rule that can't be expressed in Threat Stack.
+type falcoUnsupported struct {
+	Rule   string `json:"rule"`
+	Reason string `json:"reason"`
+}
+
+// falcoSeverities maps Falco priorities to Threat Stack severities.
+var falcoSeverities = map[string]int{
+	"emergency":     1,
+	"alert":         1,
+	"critical":      1,
+	"error":         1,
+	"warning":       2,
+	"notice":        2,
+	"informational": 3,
+	"info":          3,
+	"debug":         3,
+}
+
+// falcoFields maps Falco syscall fields to Threat Stack host event fields.
+var falcoFields = map[string]string{
+	"evt.type":     "syscall",
+	"proc.name":    "command",
+	"proc.exe":     "exe",
+	"proc.exepath": "exe",
+	"proc.args":    "arguments",
+	"proc.cmdline": "arguments",
+	"proc.pid":     "pid",
+	"proc.ppid":    "ppid",
+	"proc.cwd":     "cwd",
+	"proc.tty":     "tty",
+	"user.name":    "user",
+	"user.uid":     "uid",
+	"group.gid":    "gid",
+	"fd.name":      "filename",
+	"fd.rip":       "dst_ip",
+	"fd.rport":     "dst_port",
+	"fd.lip":       "src_ip",
+	"fd.lport":     "src_port",
+}
+
+var falcoNumericFields = map[string]bool{
+	"pid":      true,
+	"ppid":     true,
+	"uid":      true,
+	"gid":      true,
+	"dst_port": true,
+	"src_port": true,
+}
+
+// falcoFileEvents maps syscalls to the file rule events that cover them.
+var falcoFileEvents = map[string]string{
+	"open":      "open",
+	"openat":    "open",
+	"openat2":   "open",
+	"creat":     "create",
+	"unlink":    "delete",
+	"unlinkat":  "delete",
+	"rename":    "rename",
+	"renameat":  "rename",
+	"renameat2": "rename",
+	"chmod":     "attrib",
+	"fchmod":    "attrib",
+	"fchmodat":  "attrib",
+	"link":      "link",
+	"linkat":    "link",
+	"symlink":   "link",
+	"symlinkat": "link",
+}
+
+// convertFalcoRules converts Falco rules files into Threat Stack host and
+// file rules. Documents are loaded in order, so later documents can use and
+// append to the macros and lists of earlier ones, as with Falco itself.
+func convertFalcoRules(documents []string) (*falcoConversion, error) {
+	var items []*falcoItem
+	for i, doc := range documents {
+		var parsed []*falcoItem
+		if err := yaml.Unmarshal([]byte(doc), &parsed); err != nil {
+			return nil, fmt.Errorf("Error parsing Falco rules document %d: %s", i+1, err.Error())
+		}
+		items = append(items, parsed...)
+	}
+
+	env := &falcoEnv{
+		macros: map[string]string{},
+		lists:  map[string][]string{},
+	}
+
+	var rules []*falcoItem
+	ruleIndex := map[string]*falcoItem{}
+
+	for _, item := range items {
+		switch {
+		case item.List != "":
+			var values []string
+			for _, v := range item.Items {
+				values = append(values, fmt.Sprint(v))
+			}
+			if item.Append {
+				env.lists[item.List] = append(env.lists[item.List], values...)
+			} else {
+				env.lists[item.List] = values
+			}
+		case item.Macro != "":
+			if item.Append {
+				env.macros[item.Macro] += " " + item.Condition
+			} else {
+				env.macros[item.Macro] = item.Condition
+			}
+		case item.Rule != "":
+			if existing, ok := ruleIndex[item.Rule]; ok {
+				if item.Append {
+					existing.Condition += " " + item.Condition
+					existing.appendExceptions(item.Exceptions)
+				} else if item.Enabled != nil && item.Condition == "" {
+					existing.Enabled = item.Enabled
+				} else {
+					*existing = *item
+				}
+				continue
+			}
+			if item.Append {
+				return nil, fmt.Errorf("Falco rule %q is appended to before it is defined", item.Rule)
+			}
+			ruleIndex[item.Rule] = item
+			rules = append(rules, item)
+		}
+	}
+
+	ret := &falcoConversion{
+		HostRules:   []*threatstack.HostRule{},
+		FileRules:   []*threatstack.FileRule{},
+		Unsupported: []*falcoUnsupported{},
+	}
+
+	for _, rule := range rules {
+		if err := env.convertRule(rule, ret); err != nil {
+			ret.Unsupported = append(ret.Unsupported, &falcoUnsupported{Rule: rule.Rule, Reason: err.Error()})
+		}
+	}
+
+	return ret, nil
+}
+
+// appendExceptions adds the values of appended exceptions to the rule's
+// exceptions of the same name, and adds new exceptions.
+func (rule *falcoItem) appendExceptions(exceptions []*falcoException) {
+	for _, v := range exceptions {
+		found := false
+		for _, existing := range rule.Exceptions {
+			if existing.Name == v.Name {
+				existing.Values = append(existing.Values, v.Values...)
+				found = true
+				break
+			}
+		}
+		if !found {
+			rule.Exceptions = append(rule.Exceptions, v)
+		}
+	}
+}
+
+type falcoEnv struct {
+	macros map[string]string
+	lists  map[string][]string
+}
+
+func (env *falcoEnv) convertRule(rule *falcoItem, ret *falcoConversion) error {
+	if rule.Source != "" && rule.Source != "syscall" {
+		return fmt.Errorf("%s rules are not supported", rule.Source)
+	}
+
+	severity, ok := falcoSeverities[strings.ToLower(rule.Priority)]
+	if !ok {
+		return fmt.Errorf("unknown priority %q", rule.Priority)
+	}
+
+	enabled := true
+	if rule.Enabled != nil {
+		enabled = *rule.Enabled
+	}
+
+	node, err := env.parse(rule.Condition, nil)
+	if err != nil {
+		return err
+	}
+
+	// Excepted events are excluded with "and not (...)", as Falco does.
+	exceptions, err := env.exceptionsNode(rule.Exceptions)
+	if err != nil {
+		return err
+	}
+	if exceptions != nil {
+		node = &falcoLogical{op: "and", nodes: append(falcoConjuncts(node), &falcoNot{exceptions})}
+	}
+
+	// A rule that matches file events on specific paths becomes a file
+	// rule; everything else becomes a host rule.
+	if paths, events, rest, ok := falcoFileRuleParts(node); ok {
+		filter := ""
+		if len(rest) > 0 {
+			var exprs []filterExpr
+			for _, v := range rest {
+				expr, err := v.compile()
+				if err != nil {
+					return err
+				}
+				exprs = append(exprs, expr)
+			}
+			filter = joinFilterExprs(exprs, "and", filterPrecAnd).filter
+		}
+
+		ret.FileRules = append(ret.FileRules, &threatstack.FileRule{
+			Type:          "File",
+			Name:          rule.Rule,
+			Title:         rule.Rule,
+			Description:   strings.TrimSpace(rule.Desc),
+			Severity:      severity,
+			Filter:        filter,
+			Window:        falcoRuleWindow,
+			Threshold:     falcoRuleThreshold,
+			Paths:         paths,
+			MonitorEvents: events,
+			Enabled:       enabled,
+			Tags:          threatstack.NewTagSet(),
+		})
+		return nil
+	}
+
+	expr, err := node.compile()
+	if err != nil {
+		return err
+	}
+
+	ret.HostRules = append(ret.HostRules, &threatstack.HostRule{
+		Type:        "Host",
+		Name:        rule.Rule,
+		Title:       rule.Rule,
+		Description: strings.TrimSpace(rule.Desc),
+		Severity:    severity,
+		Filter:      joinFilterExprs([]filterExpr{{`event_type = "audit"`, filterPrecAtom}, expr}, "and", filterPrecAnd).filter,
+		Window:      falcoRuleWindow,
+		Threshold:   falcoRuleThreshold,
+		Enabled:     enabled,
+		Tags:        threatstack.NewTagSet(),
+	})
+	return nil
+}
+
+// exceptionsNode returns a condition that matches the events excepted by any
+// of exceptions, or nil if there are none.
+func (env *falcoEnv) exceptionsNode(exceptions []*falcoException) (falcoNode, error) {
+	var alternatives []falcoNode
+
+	for _, exception := range exceptions {
+		switch fields := exception.Fields.(type) {
+		case string:
+			comp := "in"
+			if v, ok := exception.Comps.(string); ok && v != "" {
+				comp = v
+			}
+
+			cmp, err := env.exceptionCompare(exception.Name, fields, comp, exception.Values)
+			if err != nil {
+				return nil, err
+			}
+			if cmp != nil {
+				alternatives = append(alternatives, cmp)
+			}
+		case []interface{}:
+			comps := make([]string, len(fields))
+			for i := range comps {
+				comps[i] = "="
+			}
+			if v, ok := exception.Comps.([]interface{}); ok {
+				if len(v) != len(fields) {
+					return nil, fmt.Errorf("exception %s has %d fields but %d comps", exception.Name, len(fields), len(v))
+				}
+				for i := range v {
+					comps[i] = fmt.Sprint(v[i])
+				}
+			}
+
+			for _, value := range exception.Values {
+				tuple, ok := value.([]interface{})
+				if !ok || len(tuple) != len(fields) {
+					return nil, fmt.Errorf("exception %s values must have one item per field", exception.Name)
+				}
+
+				var nodes []falcoNode
+				for i, field := range fields {
+					cmp, err := env.exceptionCompare(exception.Name, fmt.Sprint(field), comps[i], []interface{}{tuple[i]})
+					if err != nil {
+						return nil, err
+					}
+					if cmp == nil {
+						return nil, fmt.Errorf("exception %s has an empty list for %s", exception.Name, field)
+					}
+					nodes = append(nodes, cmp)
+				}
+
+				if len(nodes) == 1 {
+					alternatives = append(alternatives, nodes[0])
+				} else {
+					alternatives = append(alternatives, &falcoLogical{op: "and", nodes: nodes})
+				}
+			}
+		default:
+			return nil, fmt.Errorf("exception %s has no fields", exception.Name)
+		}
+	}
+
+	switch len(alternatives) {
+	case 0:
+		return nil, nil
+	case 1:
+		return alternatives[0], nil
+	default:
+		return &falcoLogical{op: "or", nodes: alternatives}, nil
+	}
+}
+
+// exceptionCompare returns the comparison of an exception field with values.
+// Values may be lists or list names, which are expanded. It returns nil if
+// there are no values.
+func (env *falcoEnv) exceptionCompare(name, field, comp string, values []interface{}) (falcoNode, error) {
+	if !falcoOperators[comp] {
+		return nil, fmt.Errorf("exception %s has unknown comp %q", name, comp)
+	}
+
+	var expanded []string
+	for _, v := range values {
+		if list, ok := v.([]interface{}); ok {
+			for _, item := range list {
+				expanded = append(expanded, env.expandList(fmt.Sprint(item), nil)...)
+			}
+		} else {
+			expanded = append(expanded, env.expandList(fmt.Sprint(v), nil)...)
+		}
+	}
+	if len(expanded) == 0 {
+		return nil, nil
+	}
+
+	if comp == "in" || comp == "intersects" || comp == "pmatch" {
+		return &falcoCompare{field: field, op: comp, values: expanded}, nil
+	}
+
+	var nodes []falcoNode
+	for _, v := range expanded {
+		nodes = append(nodes, &falcoCompare{field: field, op: comp, values: []string{v}})
+	}
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return &falcoLogical{op: "or", nodes: nodes}, nil
+}
+
+// falcoNode is a node of a parsed Falco condition.
+type falcoNode interface {
+	compile() (filterExpr, error)
+}
+
+type falcoLogical struct {
+	op    string
+	nodes []falcoNode
+}
+
+type falcoNot struct {
+	node falcoNode
+}
+
+type falcoCompare struct {
+	field  string
+	op     string
+	values []string
+}
+
+func (n *falcoLogical) compile() (filterExpr, error) {
+	prec := filterPrecOr
+	if n.op == "and" {
+		prec = filterPrecAnd
+	}
+
+	var exprs []filterExpr
+	for _, v := range n.nodes {
+		expr, err := v.compile()
+		if err != nil {
+			return filterExpr{}, err
+		}
+		exprs = append(exprs, expr)
+	}
+	return joinFilterExprs(exprs, n.op, prec), nil
+}
+
+func (n *falcoNot) compile() (filterExpr, error) {
+	expr, err := n.node.compile()
+	if err != nil {
+		return filterExpr{}, err
+	}
+	return notFilterExpr(expr), nil
+}
+
+func (n *falcoCompare) compile() (filterExpr, error) {
+	field, ok := falcoFields[n.field]
+	if !ok {
+		return filterExpr{}, fmt.Errorf("field %s has no Threat Stack equivalent", n.field)
+	}
+
+	value := func(v string) string {
+		if _, err := strconv.Atoi(v); err == nil && falcoNumericFields[field] {
+			return v
+		}
+		return strconv.Quote(v)
+	}
+
+	like := func(pattern string) (filterExpr, error) {
+		if strings.ContainsAny(n.values[0], "%_") {
+			return filterExpr{}, fmt.Errorf("value %q contains %% or _, which can't be used in a pattern", n.values[0])
+		}
+		return filterExpr{fmt.Sprintf("%s LIKE %s", field, strconv.Quote(pattern)), filterPrecAtom}, nil
+	}
+
+	switch n.op {
+	case "=", "!=", "<", "<=", ">", ">=":
+		return filterExpr{fmt.Sprintf("%s %s %s", field, n.op, value(n.values[0])), filterPrecAtom}, nil
+	case "contains":
+		return like("%" + n.values[0] + "%")
+	case "startswith":
+		return like(n.values[0] + "%")
+	case "endswith":
+		return like("%" + n.values[0])
+	case "in":
+		var exprs []filterExpr
+		for _, v := range n.values {
+			exprs = append(exprs, filterExpr{fmt.Sprintf("%s = %s", field, value(v)), filterPrecAtom})
+		}
+		if len(exprs) == 0 {
+			return filterExpr{}, fmt.Errorf("empty list for %s", n.field)
+		}
+		return joinFilterExprs(exprs, "or", filterPrecOr), nil
+	default:
+		return filterExpr{}, fmt.Errorf("operator %s has no Threat Stack equivalent", n.op)
+	}
+}
+
+// falcoFileRuleParts splits a condition into the parts of a file rule: the
+// monitored paths, the file events and the remaining conditions. It returns
+// false if the condition doesn't match file events on specific paths.
+func falcoFileRuleParts(node falcoNode) ([]*threatstack.FilePath, []string, []falcoNode, bool) {
+	var paths []*threatstack.FilePath
+	events := map[string]bool{}
+	openMode := ""
+	var rest []falcoNode
+
+	for _, v := range falcoConjuncts(node) {
+		cmp, ok := v.(*falcoCompare)
+		if !ok {
+			rest = append(rest, v)
+			continue
+		}
+
+		switch {
+		case cmp.field == "fd.name" && (cmp.op == "=" || cmp.op == "in"):
+			for _, path := range cmp.values {
+				paths = append(paths, &threatstack.FilePath{Path: path})
+			}
+		case cmp.field == "fd.name" && cmp.op == "startswith":
+			paths = append(paths, &threatstack.FilePath{Path: strings.TrimSuffix(cmp.values[0], "/"), Recursive: true})
+		case cmp.field == "fd.directory" && (cmp.op == "=" || cmp.op == "in"):
+			for _, path := range cmp.values {
+				paths = append(paths, &threatstack.FilePath{Path: path})
+			}
+		case cmp.field == "evt.type" && (cmp.op == "=" || cmp.op == "in"):
+			for _, syscall := range cmp.values {
+				event, ok := falcoFileEvents[syscall]
+				if !ok {
+					return nil, nil, nil, false
+				}
+				events[event] = true
+			}
+		case cmp.field == "evt.is_open_write" && cmp.op == "=" && cmp.values[0] == "true":
+			openMode = "write"
+		case cmp.field == "evt.is_open_read" && cmp.op == "=" && cmp.values[0] == "true":
+			openMode = "open"
+		case cmp.field == "fd.typechar" || cmp.field == "fd.num":
+			// Implied by a file rule.
+		default:
+			rest = append(rest, v)
+		}
+	}
+
+	if openMode != "" {
+		delete(events, "open")
+		events[openMode] = true
+	}
+
+	if len(paths) == 0 || len(events) == 0 {
+		return nil, nil, nil, false
+	}
+
+	var eventList []string
+	for k := range events {
+		eventList = append(eventList, k)
+	}
+	sort.Strings(eventList)
+
+	return paths, eventList, rest, true
+}
+
+func falcoConjuncts(node falcoNode) []falcoNode {
+	if n, ok := node.(*falcoLogical); ok && n.op == "and" {
+		var ret []falcoNode
+		for _, v := range n.nodes {
+			ret = append(ret, falcoConjuncts(v)...)
+		}
+		return ret
+	}
+	return []falcoNode{node}
+}
+
+var falcoConditionToken = regexp.MustCompile(`"(?:[^"\\]|\\.)*"|'[^']*'|!=|<=|>=|[=<>(),]|[^\s=<>!(),"']+`)
+
+var falcoUnaryOperators = map[string]bool{
+	"exists": true,
+}
+
+var falcoOperators = map[string]bool{
+	"=":          true,
+	"!=":         true,
+	"<":          true,
+	"<=":         true,
+	">":          true,
+	">=":         true,
+	"contains":   true,
+	"icontains":  true,
+	"bcontains":  true,
+	"startswith": true,
+	"endswith":   true,
+	"glob":       true,
+	"in":         true,
+	"intersects": true,
+	"pmatch":     true,
+}
+
+// falcoParser is a recursive descent parser for Falco conditions, expanding
+// macros and lists as it goes:
+//
+//	expr    = and { "or" and }
+//	and     = not { "and" not }
+//	not     = "not" not | primary
+//	primary = "(" expr ")" | field operator [value | list] | macro
+type falcoParser struct {
+	env    *falcoEnv
+	tokens []string
+	pos    int
+	stack  []string
+}
+
+func (env *falcoEnv) parse(condition string, stack []string) (falcoNode, error) {
+	parser := &falcoParser{
+		env:    env,
+		tokens: falcoConditionToken.FindAllString(condition, -1),
+		stack:  stack,
+	}
+
+	node, err := parser.or()
+	if err != nil {
+		return nil, err
+	}
+	if parser.pos < len(parser.tokens) {
+		return nil, fmt.Errorf("unexpected %q in condition", parser.tokens[parser.pos])
+	}
+
+	return node, nil
+}
+
+func (p *falcoParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *falcoParser) next() string {
+	token := p.peek()
+	p.pos++
+	return token
+}
+
+func (p *falcoParser) or() (falcoNode, error) {
+	return p.logical("or", p.and)
+}
+
+func (p *falcoParser) and() (falcoNode, error) {
+	return p.logical("and", p.not)
+}
+
+func (p *falcoParser) logical(op string, operand func() (falcoNode, error)) (falcoNode, error) {
+	node, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := []falcoNode{node}
+	for p.peek() == op {
+		p.next()
+		node, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return &falcoLogical{op: op, nodes: nodes}, nil
+}
+
+func (p *falcoParser) not() (falcoNode, error) {
+	if p.peek() != "not" {
+		return p.primary()
+	}
+	p.next()
+
+	node, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+	return &falcoNot{node}, nil
+}
+
+func (p *falcoParser) primary() (falcoNode, error) {
+	token := p.next()
+
+	switch token {
+	case "":
+		return nil, fmt.Errorf("unexpected end of condition")
+	case "(":
+		node, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, fmt.Errorf("missing ) in condition")
+		}
+		return node, nil
+	case ")", ",", "and", "or":
+		return nil, fmt.Errorf("unexpected %q in condition", token)
+	}
+
+	op := p.peek()
+	switch {
+	case falcoUnaryOperators[op]:
+		return nil, fmt.Errorf("operator %s has no Threat Stack equivalent", op)
+	case falcoOperators[op]:
+		p.next()
+		return p.compare(token, op)
+	}
+
+	return p.macro(token)
+}
+
+func (p *falcoParser) compare(field, op string) (falcoNode, error) {
+	if op == "in" || op == "intersects" || op == "pmatch" {
+		if p.next() != "(" {
+			return nil, fmt.Errorf("expected list after %s %s", field, op)
+		}
+
+		var values []string
+		for p.peek() != ")" {
+			item := p.next()
+			if item == "" {
+				return nil, fmt.Errorf("missing ) in list")
+			}
+			if item == "," {
+				continue
+			}
+			values = append(values, p.env.expandList(falcoUnquote(item), nil)...)
+		}
+		p.next()
+
+		return &falcoCompare{field: field, op: op, values: values}, nil
+	}
+
+	value := p.next()
+	if value == "" {
+		return nil, fmt.Errorf("missing value after %s %s", field, op)
+	}
+	return &falcoCompare{field: field, op: op, values: []string{falcoUnquote(value)}}, nil
+}
+
+func (p *falcoParser) macro(name string) (falcoNode, error) {
+	condition, ok := p.env.macros[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown macro or field %s", name)
+	}
+
+	for _, v := range p.stack {
+		if v == name {
+			return nil, fmt.Errorf("macro %s refers to itself", name)
+		}
+	}
+
+	node, err := p.env.parse(condition, append(append([]string{}, p.stack...), name))
+	if err != nil {
+		return nil, fmt.Errorf("in macro %s: %s", name, err.Error())
+	}
+	return node, nil
+}
+
+// expandList replaces list names with their items, recursively.
+func (env *falcoEnv) expandList(item string, stack []string) []string {
+	items, ok := env.lists[item]
+	if !ok {
+		return []string{item}
+	}
+
+	for _, v := range stack {
+		if v == item {
+			return nil
+		}
+	}
+
+	var ret []string
+	for _, v := range items {
+		ret = append(ret, env.expandList(v, append(append([]string{}, stack...), item))...)
+	}
+	return ret
+}
+
+func falcoUnquote(s string) string {
+	if len(s) >= 2 && s[0] == '"' {
+		if unquoted, err := strconv.Unquote(s); err == nil {
+			return unquoted
+		}
+	}
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
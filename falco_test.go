@@ -0,0 +1,187 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jfcantu/threatstack-golang/threatstack"
+)
+
+const testFalcoMacros = `
+- list: shell_binaries
+  items: [bash, sh, zsh]
+
+- list: nc_binaries
+  items: [nc, ncat]
+
+- macro: spawned_process
+  condition: evt.type = execve
+
+- macro: open_write
+  condition: (evt.type in (open, openat) and evt.is_open_write=true and fd.typechar='f')
+`
+
+const testFalcoRules = `
+- rule: Shell from netcat
+  desc: A shell was spawned by netcat.
+  condition: spawned_process and proc.name in (shell_binaries, nc_binaries) and not user.name = root
+  output: Shell spawned (user=%user.name)
+  priority: WARNING
+
+- rule: Write below etc
+  desc: A file below /etc was opened for writing.
+  condition: open_write and fd.name startswith /etc/ and proc.name != "dpkg"
+  priority: ERROR
+
+- rule: Parent check
+  desc: Unsupported field.
+  condition: spawned_process and proc.pname = sshd
+  priority: NOTICE
+
+- rule: K8s exec
+  desc: Not a syscall rule.
+  condition: ka.verb = create
+  source: k8s_audit
+  priority: NOTICE
+
+- rule: Parent check
+  enabled: false
+`
+
+func TestConvertFalcoRules(test *testing.T) {
+	conversion, err := convertFalcoRules([]string{testFalcoMacros, testFalcoRules})
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	expectedHost := []*threatstack.HostRule{
+		{
+			Type:        "Host",
+			Name:        "Shell from netcat",
+			Title:       "Shell from netcat",
+			Description: "A shell was spawned by netcat.",
+			Severity:    2,
+			Filter: `event_type = "audit" and syscall = "execve" and ` +
+				`(command = "bash" or command = "sh" or command = "zsh" or command = "nc" or command = "ncat") and ` +
+				`not (user = "root")`,
+			Window:    falcoRuleWindow,
+			Threshold: falcoRuleThreshold,
+			Enabled:   true,
+			Tags:      threatstack.NewTagSet(),
+		},
+	}
+	if !reflect.DeepEqual(conversion.HostRules, expectedHost) {
+		test.Errorf("Host rules:\n%#v\n\nExpected:\n%#v", conversion.HostRules[0], expectedHost[0])
+	}
+
+	expectedFile := []*threatstack.FileRule{
+		{
+			Type:          "File",
+			Name:          "Write below etc",
+			Title:         "Write below etc",
+			Description:   "A file below /etc was opened for writing.",
+			Severity:      1,
+			Filter:        `command != "dpkg"`,
+			Window:        falcoRuleWindow,
+			Threshold:     falcoRuleThreshold,
+			Paths:         []*threatstack.FilePath{{Path: "/etc", Recursive: true}},
+			MonitorEvents: []string{"write"},
+			Enabled:       true,
+			Tags:          threatstack.NewTagSet(),
+		},
+	}
+	if !reflect.DeepEqual(conversion.FileRules, expectedFile) {
+		test.Errorf("File rules:\n%#v\n\nExpected:\n%#v", conversion.FileRules[0], expectedFile[0])
+	}
+
+	if len(conversion.Unsupported) != 2 {
+		test.Fatalf("Unsupported: %#v", conversion.Unsupported)
+	}
+	for i, expected := range []string{"field proc.pname has no Threat Stack equivalent", "k8s_audit rules are not supported"} {
+		if !strings.Contains(conversion.Unsupported[i].Reason, expected) {
+			test.Errorf("Expected %q, got %q", expected, conversion.Unsupported[i].Reason)
+		}
+	}
+}
+
+func TestConvertFalcoRulesUnsupportedOperators(test *testing.T) {
+	for _, v := range []struct {
+		condition string
+		expected  string
+	}{
+		{"proc.name icontains nc", "operator icontains"},
+		{"proc.name pmatch (/tmp)", "operator pmatch"},
+		{"proc.name exists", "operator exists"},
+		{"recursive_macro", "refers to itself"},
+		{"undefined_macro", "unknown macro or field undefined_macro"},
+	} {
+		conversion, err := convertFalcoRules([]string{`
+- macro: recursive_macro
+  condition: recursive_macro
+- rule: test
+  desc: test
+  condition: ` + v.condition + `
+  priority: INFO
+`})
+		if err != nil {
+			test.Fatal(err)
+		}
+
+		if len(conversion.Unsupported) != 1 || !strings.Contains(conversion.Unsupported[0].Reason, v.expected) {
+			test.Errorf("%s: expected unsupported reason containing %q, got %#v", v.condition, v.expected, conversion.Unsupported)
+		}
+	}
+}
+
+func TestConvertFalcoRulesExceptions(test *testing.T) {
+	conversion, err := convertFalcoRules([]string{testFalcoMacros, `
+- rule: Shell spawned
+  desc: A shell was spawned.
+  condition: spawned_process and proc.name in (shell_binaries)
+  exceptions:
+    - name: admin_shells
+      fields: [user.name, proc.cwd]
+      values:
+        - [root, /root]
+    - name: known_users
+      fields: user.name
+      values: [deploy]
+  priority: WARNING
+
+- rule: Shell spawned
+  append: true
+  exceptions:
+    - name: admin_shells
+      values:
+        - [admin, /home/admin]
+
+- rule: Shell with parent exception
+  desc: Unsupported exception field.
+  condition: spawned_process
+  exceptions:
+    - name: from_sshd
+      fields: [proc.pname]
+      values:
+        - [sshd]
+  priority: WARNING
+`})
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	if len(conversion.HostRules) != 1 {
+		test.Fatalf("Host rules: %#v", conversion.HostRules)
+	}
+
+	expected := `event_type = "audit" and syscall = "execve" and ` +
+		`(command = "bash" or command = "sh" or command = "zsh") and ` +
+		`not (user = "root" and cwd = "/root" or user = "admin" and cwd = "/home/admin" or user = "deploy")`
+	if got := conversion.HostRules[0].Filter; got != expected {
+		test.Errorf("Expected filter:\n%s\ngot:\n%s", expected, got)
+	}
+
+	if len(conversion.Unsupported) != 1 || !strings.Contains(conversion.Unsupported[0].Reason, "field proc.pname has no Threat Stack equivalent") {
+		test.Errorf("Expected the rule with an unsupported exception to be unsupported, got %#v", conversion.Unsupported)
+	}
+}
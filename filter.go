@@ -0,0 +1,43 @@
+package main
+
+import (
+	"strings"
+)
+
+// Operator precedence of Threat Stack filter expressions, used to decide
+// where parentheses are needed when translating rules from other formats.
+const (
+	filterPrecOr = iota
+	filterPrecAnd
+	filterPrecAtom
+)
+
+// filterExpr is a Threat Stack filter expression along with the precedence
+// of its outermost operator.
+type filterExpr struct {
+	filter string
+	prec   int
+}
+
+func (e filterExpr) wrap(prec int) string {
+	if e.prec < prec {
+		return "(" + e.filter + ")"
+	}
+	return e.filter
+}
+
+func joinFilterExprs(exprs []filterExpr, op string, prec int) filterExpr {
+	if len(exprs) == 1 {
+		return exprs[0]
+	}
+
+	var parts []string
+	for _, v := range exprs {
+		parts = append(parts, v.wrap(prec))
+	}
+	return filterExpr{strings.Join(parts, " "+op+" "), prec}
+}
+
+func notFilterExpr(expr filterExpr) filterExpr {
+	return filterExpr{"not (" + expr.filter + ")", filterPrecAtom}
+}
@@ -7,4 +7,5 @@ require (
 	github.com/hashicorp/terraform-plugin-sdk v1.9.0
 	github.com/jfcantu/threatstack-golang v0.1.4
 	github.com/sirupsen/logrus v1.4.2
+	gopkg.in/yaml.v2 v2.2.4
 )
@@ -0,0 +1,316 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/jfcantu/threatstack-golang/threatstack"
+)
+
+// Lint severities, in increasing order of importance. A check with severity
+// "off" isn't run.
+const (
+	lintSeverityOff     = "off"
+	lintSeverityWarning = "warning"
+	lintSeverityError   = "error"
+)
+
+var lintSeverities = []string{lintSeverityOff, lintSeverityWarning, lintSeverityError}
+
+// lintCheck is a single lint check. Each check implements Host, File or both,
+// returning a message for each problem found.
+type lintCheck struct {
+	Name        string
+	Description string
+	Severity    string
+	Host        func(rule *threatstack.HostRule) []string
+	File        func(rule *threatstack.FileRule) []string
+}
+
+// lintChecks is every available check. New checks only need to be added here.
+var lintChecks = []*lintCheck{
+	{
+		Name:        "every-event",
+		Description: "Rules with a threshold of 1 and the shortest window alert on every matching event",
+		Severity:    lintSeverityWarning,
+		Host: func(rule *threatstack.HostRule) []string {
+			return lintEveryEvent(rule.Threshold, rule.Window)
+		},
+		File: func(rule *threatstack.FileRule) []string {
+			return lintEveryEvent(rule.Threshold, rule.Window)
+		},
+	},
+	{
+		Name:        "broad-filter",
+		Description: "Rules that only filter on event_type, with no suppressions, match every event of that type",
+		Severity:    lintSeverityWarning,
+		Host: func(rule *threatstack.HostRule) []string {
+			return lintBroadFilter(rule.Filter, rule.Suppressions)
+		},
+		File: func(rule *threatstack.FileRule) []string {
+			return lintBroadFilter(rule.Filter, rule.Suppressions)
+		},
+	},
+	{
+		Name:        "fim-root",
+		Description: "File rules that recursively monitor / watch every file on the host",
+		Severity:    lintSeverityWarning,
+		File: func(rule *threatstack.FileRule) []string {
+			var ret []string
+			for _, v := range rule.Paths {
+				if v.Recursive && path.Clean(v.Path) == "/" {
+					ret = append(ret, fmt.Sprintf("file_path %q is monitored recursively", v.Path))
+				}
+			}
+			return ret
+		},
+	},
+}
+
+func lintEveryEvent(threshold, window int) []string {
+	if threshold <= 1 && window <= getValidRuleWindows()[0] {
+		return []string{fmt.Sprintf("threshold %d with window %d alerts on every matching event", threshold, window)}
+	}
+	return nil
+}
+
+var (
+	lintEventTypePattern = regexp.MustCompile(`(?i)event_type\s*=\s*("[^"]*"|'[^']*')`)
+	lintConnectives      = regexp.MustCompile(`(?i)\b(and|or)\b|[()\s]`)
+)
+
+func lintBroadFilter(filter string, suppressions []string) []string {
+	if len(suppressions) > 0 {
+		return nil
+	}
+
+	rest := lintEventTypePattern.ReplaceAllString(filter, "")
+	if lintConnectives.ReplaceAllString(rest, "") != "" {
+		return nil
+	}
+
+	return []string{fmt.Sprintf("filter %q only matches on event_type and there are no suppressions", filter)}
+}
+
+func lintCheckNames() []string {
+	var ret []string
+	for _, v := range lintChecks {
+		ret = append(ret, v.Name)
+	}
+	return ret
+}
+
+// lintConfig overrides the severity of checks. Ignored checks aren't run.
+type lintConfig struct {
+	Severity map[string]string
+	Ignore   map[string]bool
+}
+
+// newLintConfig validates check names and severities and returns the
+// resulting configuration.
+func newLintConfig(ignore []string, severity map[string]string) (*lintConfig, error) {
+	config := &lintConfig{
+		Severity: map[string]string{},
+		Ignore:   map[string]bool{},
+	}
+
+	known := map[string]bool{}
+	for _, v := range lintChecks {
+		known[v.Name] = true
+	}
+
+	for _, name := range ignore {
+		if !known[name] {
+			return nil, fmt.Errorf("Unknown lint check %q, expected one of: %s", name, strings.Join(lintCheckNames(), ", "))
+		}
+		config.Ignore[name] = true
+	}
+
+	for name, level := range severity {
+		if !known[name] {
+			return nil, fmt.Errorf("Unknown lint check %q, expected one of: %s", name, strings.Join(lintCheckNames(), ", "))
+		}
+		if !stringInSlice(level, lintSeverities) {
+			return nil, fmt.Errorf("Invalid severity %q for lint check %s, expected one of: %s", level, name, strings.Join(lintSeverities, ", "))
+		}
+		config.Severity[name] = level
+	}
+
+	return config, nil
+}
+
+// lintFinding is a problem found by a check. Rule identifies the rule to the
+// user, and is set by the caller.
+type lintFinding struct {
+	Rule     string `json:"rule,omitempty"`
+	Check    string `json:"check"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+func (f *lintFinding) String() string {
+	if f.Rule == "" {
+		return fmt.Sprintf("%s: %s: %s", f.Severity, f.Check, f.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s: %s", f.Severity, f.Rule, f.Check, f.Message)
+}
+
+// lintRule runs every enabled check against a rule. ignore lists additional
+// checks to skip for this rule only.
+func (config *lintConfig) lintRule(rule threatstack.Rule, ignore []string) []*lintFinding {
+	var ret []*lintFinding
+	for _, check := range lintChecks {
+		severity := check.Severity
+		if config != nil {
+			if config.Ignore[check.Name] {
+				continue
+			}
+			if v, ok := config.Severity[check.Name]; ok {
+				severity = v
+			}
+		}
+		if severity == lintSeverityOff || stringInSlice(check.Name, ignore) {
+			continue
+		}
+
+		var messages []string
+		switch r := rule.(type) {
+		case *threatstack.HostRule:
+			if check.Host != nil {
+				messages = check.Host(r)
+			}
+		case *threatstack.FileRule:
+			if check.File != nil {
+				messages = check.File(r)
+			}
+		}
+
+		for _, v := range messages {
+			ret = append(ret, &lintFinding{
+				Check:    check.Name,
+				Severity: severity,
+				Message:  v,
+			})
+		}
+	}
+
+	return ret
+}
+
+// sortLintFindings sorts findings by rule, then check.
+func sortLintFindings(findings []*lintFinding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Rule != findings[j].Rule {
+			return findings[i].Rule < findings[j].Rule
+		}
+		return findings[i].Check < findings[j].Check
+	})
+}
+
+// lintIgnoreSchema is the lint_ignore argument of the rule resources.
+func lintIgnoreSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeSet,
+		Optional: true,
+		Elem: &schema.Schema{
+			Type:         schema.TypeString,
+			ValidateFunc: validation.StringInSlice(lintCheckNames(), false),
+		},
+	}
+}
+
+// customizeDiffLint lints the planned rule built by expand. Findings with
+// severity "error" fail the plan. CustomizeDiff has no way to return
+// warnings, so those are only logged. Linting is skipped while any of keys
+// is unknown.
+func customizeDiffLint(expand func(d *schema.ResourceDiff) threatstack.Rule, keys ...string) schema.CustomizeDiffFunc {
+	return func(d *schema.ResourceDiff, meta interface{}) error {
+		for _, k := range keys {
+			if !d.NewValueKnown(k) {
+				log.Printf("[DEBUG] Skipping lint of %s, %s is not known until apply", d.Get("name"), k)
+				return nil
+			}
+		}
+
+		var config *lintConfig
+		if m, ok := meta.(*providerMeta); ok {
+			config = m.Lint
+		}
+
+		var ignore []string
+		for _, v := range d.Get("lint_ignore").(*schema.Set).List() {
+			ignore = append(ignore, v.(string))
+		}
+
+		var errs []string
+		for _, v := range config.lintRule(expand(d), ignore) {
+			v.Rule = d.Get("name").(string)
+			if v.Severity == lintSeverityError {
+				errs = append(errs, v.String())
+			} else {
+				log.Printf("[WARN] Lint %s", v.String())
+			}
+		}
+
+		if len(errs) > 0 {
+			return fmt.Errorf("Rule failed lint checks:\n%s", strings.Join(errs, "\n"))
+		}
+		return nil
+	}
+}
+
+// lintHostRuleDiff builds the fields of a planned host rule that lint checks
+// look at.
+func lintHostRuleDiff(d *schema.ResourceDiff) threatstack.Rule {
+	return &threatstack.HostRule{
+		Name:         d.Get("name").(string),
+		Filter:       d.Get("filter").(string),
+		Window:       d.Get("window").(int),
+		Threshold:    d.Get("threshold").(int),
+		Suppressions: lintStringSet(d.Get("suppressions")),
+	}
+}
+
+// lintFileRuleDiff builds the fields of a planned file rule that lint checks
+// look at.
+func lintFileRuleDiff(d *schema.ResourceDiff) threatstack.Rule {
+	var paths []*threatstack.FilePath
+	for _, v := range d.Get("file_path").(*schema.Set).List() {
+		paths = append(paths, &threatstack.FilePath{
+			Path:      v.(map[string]interface{})["path"].(string),
+			Recursive: v.(map[string]interface{})["recursive"].(bool),
+		})
+	}
+
+	return &threatstack.FileRule{
+		Name:         d.Get("name").(string),
+		Filter:       d.Get("filter").(string),
+		Window:       d.Get("window").(int),
+		Threshold:    d.Get("threshold").(int),
+		Suppressions: lintStringSet(d.Get("suppressions")),
+		Paths:        paths,
+	}
+}
+
+func lintStringSet(v interface{}) []string {
+	var ret []string
+	for _, s := range v.(*schema.Set).List() {
+		ret = append(ret, s.(string))
+	}
+	return ret
+}
+
+func stringInSlice(s string, list []string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
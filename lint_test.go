@@ -0,0 +1,136 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/jfcantu/threatstack-golang/threatstack"
+)
+
+func lintFindingChecks(findings []*lintFinding) []string {
+	var ret []string
+	for _, v := range findings {
+		ret = append(ret, v.Check+"="+v.Severity)
+	}
+	return ret
+}
+
+func TestLintRule(test *testing.T) {
+	for _, v := range []struct {
+		rule     threatstack.Rule
+		expected []string
+	}{
+		{
+			&threatstack.HostRule{Filter: `event_type = "host"`, Window: 3600, Threshold: 1},
+			[]string{"every-event=warning", "broad-filter=warning"},
+		},
+		{
+			&threatstack.HostRule{Filter: `(event_type = 'audit' or event_type = "host")`, Window: 86400, Threshold: 5},
+			[]string{"broad-filter=warning"},
+		},
+		{
+			&threatstack.HostRule{Filter: `event_type = "host"`, Window: 3600, Threshold: 10, Suppressions: []string{`user = "root"`}},
+			nil,
+		},
+		{
+			&threatstack.HostRule{Filter: `event_type = "host" and sigid = "5902"`, Window: 7200, Threshold: 1},
+			nil,
+		},
+		{
+			&threatstack.FileRule{
+				Filter:    `user != "root"`,
+				Window:    3600,
+				Threshold: 2,
+				Paths: []*threatstack.FilePath{
+					{Path: "/", Recursive: false},
+					{Path: "//", Recursive: true},
+					{Path: "/etc", Recursive: true},
+				},
+			},
+			[]string{"fim-root=warning"},
+		},
+	} {
+		var config *lintConfig
+		if got := lintFindingChecks(config.lintRule(v.rule, nil)); !reflect.DeepEqual(got, v.expected) {
+			test.Errorf("Expected %v for %#v, got %v", v.expected, v.rule, got)
+		}
+	}
+}
+
+func TestLintConfig(test *testing.T) {
+	rule := &threatstack.FileRule{
+		Filter:    `event_type = "file"`,
+		Window:    3600,
+		Threshold: 1,
+		Paths:     []*threatstack.FilePath{{Path: "/", Recursive: true}},
+	}
+
+	config, err := parseLintFlags("every-event", "fim-root=error, broad-filter=off")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	expected := []string{"fim-root=error"}
+	if got := lintFindingChecks(config.lintRule(rule, nil)); !reflect.DeepEqual(got, expected) {
+		test.Errorf("Expected %v, got %v", expected, got)
+	}
+
+	if got := config.lintRule(rule, []string{"fim-root"}); len(got) != 0 {
+		test.Errorf("Expected no findings with fim-root ignored, got %v", lintFindingChecks(got))
+	}
+
+	for _, v := range []struct {
+		ignore   string
+		severity string
+		expected string
+	}{
+		{"bogus", "", `Unknown lint check "bogus"`},
+		{"", "bogus=error", `Unknown lint check "bogus"`},
+		{"", "fim-root=fatal", `Invalid severity "fatal"`},
+		{"", "fim-root", "expected check=severity"},
+	} {
+		_, err := parseLintFlags(v.ignore, v.severity)
+		if err == nil || !strings.Contains(err.Error(), v.expected) {
+			test.Errorf("Expected error containing %q, got %v", v.expected, err)
+		}
+	}
+}
+
+func TestLintCustomizeDiff(test *testing.T) {
+	raw := map[string]interface{}{
+		"name":      "noisy",
+		"title":     "noisy",
+		"ruleset":   "ruleset",
+		"severity":  1,
+		"filter":    `event_type = "file"`,
+		"window":    3600,
+		"threshold": 5,
+		"file_path": []interface{}{
+			map[string]interface{}{"path": "/", "recursive": true},
+		},
+		"monitor_events": []interface{}{"open"},
+	}
+
+	config, err := newLintConfig(nil, map[string]string{"fim-root": "error"})
+	if err != nil {
+		test.Fatal(err)
+	}
+	meta := &providerMeta{Lint: config}
+
+	_, err = resourceFileRule().Diff(nil, terraform.NewResourceConfigRaw(raw), meta)
+	if err == nil || !strings.Contains(err.Error(), "noisy: fim-root:") {
+		test.Errorf("Expected fim-root lint error, got %v", err)
+	}
+
+	// Warnings don't fail the plan.
+	if _, err := resourceFileRule().Diff(nil, terraform.NewResourceConfigRaw(raw), nil); err != nil {
+		test.Errorf("Expected no error without lint configuration, got %v", err)
+	}
+
+	raw["lint_ignore"] = []interface{}{"fim-root"}
+	if _, err := resourceFileRule().Diff(nil, terraform.NewResourceConfigRaw(raw), meta); err != nil {
+		test.Errorf("Expected no error with fim-root ignored, got %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/jfcantu/threatstack-golang/threatstack"
+)
+
+// organizationIDSchema is the schema of the organization_id argument of
+// resources, which selects the organization the resource is managed in.
+func organizationIDSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		ForceNew:    true,
+		Description: "The organization to manage the resource in. Defaults to the provider's organization_id.",
+	}
+}
+
+// dataSourceOrganizationIDSchema is organizationIDSchema for data sources,
+// which are never replaced.
+func dataSourceOrganizationIDSchema() *schema.Schema {
+	s := organizationIDSchema()
+	s.ForceNew = false
+	s.Description = "The organization to read from. Defaults to the provider's organization_id."
+	return s
+}
+
+// resourceClient returns the client for a resource's organization_id.
+func resourceClient(resourceData *schema.ResourceData, meta interface{}) (*threatstack.Client, error) {
+	return meta.(*providerMeta).OrgClient(resourceData.Get("organization_id").(string))
+}
+
+// importStateOrganization wraps an importer so that the import ID may be
+// prefixed with an organization ID and a colon, e.g. "<organization ID>:<ID>",
+// for resources in an organization other than the provider's. A prefix of the
+// provider's own organization leaves organization_id empty, so that a
+// configuration without it doesn't plan a replacement.
+func importStateOrganization(next schema.StateFunc) schema.StateFunc {
+	return func(resourceData *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+		if parts := strings.SplitN(resourceData.Id(), ":", 2); len(parts) == 2 && parts[0] != "" {
+			if !meta.(*providerMeta).IsOwnOrganization(parts[0]) {
+				resourceData.Set("organization_id", parts[0])
+			}
+			resourceData.SetId(parts[1])
+		}
+		return next(resourceData, meta)
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/jfcantu/threatstack-golang/threatstack"
+)
+
+func TestProviderMetaOrgClient(test *testing.T) {
+	defaultClient := &threatstack.Client{}
+	meta := &providerMeta{
+		Client: defaultClient,
+		config: Config{APIKey: "key", OrganizationID: "prod", UserID: "user"},
+		credentials: map[string]Config{
+			"acquired": {APIKey: "other-key", OrganizationID: "acquired", UserID: "other-user"},
+		},
+	}
+
+	for _, orgID := range []string{"", "prod"} {
+		client, err := meta.OrgClient(orgID)
+		if err != nil {
+			test.Fatal(err)
+		}
+		if client != defaultClient {
+			test.Errorf("Expected the provider's client for organization %q", orgID)
+		}
+	}
+
+	for _, orgID := range []string{"staging", "acquired"} {
+		client, err := meta.OrgClient(orgID)
+		if err != nil {
+			test.Fatal(err)
+		}
+		if client == defaultClient {
+			test.Errorf("Expected a separate client for organization %s", orgID)
+		}
+
+		again, err := meta.OrgClient(orgID)
+		if err != nil {
+			test.Fatal(err)
+		}
+		if again != client {
+			test.Errorf("Expected the client for organization %s to be cached", orgID)
+		}
+	}
+
+	if len(meta.clients) != 2 {
+		test.Errorf("Expected 2 cached clients, got %d", len(meta.clients))
+	}
+}
+
+func TestImportStateOrganization(test *testing.T) {
+	importer := importStateOrganization(resourceRuleImportState)
+
+	client := testAPIClient(test, map[string]string{
+		"/v2/rulesets/rs1/rules/r1": `{"id": "r1", "type": "Host"}`,
+	})
+	meta := &providerMeta{
+		Client:  client,
+		config:  Config{OrganizationID: "prod"},
+		clients: map[string]*threatstack.Client{"staging": client},
+	}
+
+	for _, v := range []struct {
+		id      string
+		orgID   string
+		ruleset string
+		ruleID  string
+	}{
+		{"rs1/r1", "", "rs1", "r1"},
+		{"staging:rs1/r1", "staging", "rs1", "r1"},
+		{"prod:rs1/r1", "", "rs1", "r1"},
+	} {
+		resourceData := schema.TestResourceDataRaw(test, resourceHostRule().Schema, map[string]interface{}{})
+		resourceData.SetId(v.id)
+
+		if _, err := importer(resourceData, meta); err != nil {
+			test.Fatal(err)
+		}
+
+		if got := resourceData.Get("organization_id"); got != v.orgID {
+			test.Errorf("Expected organization %q for %s, got %q", v.orgID, v.id, got)
+		}
+		if got := resourceData.Get("ruleset"); got != v.ruleset {
+			test.Errorf("Expected ruleset %q for %s, got %q", v.ruleset, v.id, got)
+		}
+		if resourceData.Id() != v.ruleID {
+			test.Errorf("Expected ID %q for %s, got %q", v.ruleID, v.id, resourceData.Id())
+		}
+	}
+}
+
+func TestDataSourceIDsIncludeOrganization(test *testing.T) {
+	client := testAPIClient(test, map[string]string{
+		"/v2/alerts?ruleId=r1&status=active":      `{"alerts": []}`,
+		"/v2/alerts?ruleId=r1&status=dismissed":   `{"alerts": []}`,
+		"/v2/auditlogs?objectId=r1":               `{"auditLogs": []}`,
+		"/v2/vulnerabilities?packageName=openssl": `{"cves": []}`,
+	})
+	meta := &providerMeta{
+		Client:  client,
+		clients: map[string]*threatstack.Client{"staging": client},
+	}
+
+	for _, v := range []struct {
+		resource *schema.Resource
+		raw      map[string]interface{}
+	}{
+		{dataSourceAlerts(), map[string]interface{}{"rule_id": "r1"}},
+		{dataSourceAuditLogs(), map[string]interface{}{"object_id": "r1"}},
+		{dataSourceVulnerabilities(), map[string]interface{}{"package": "openssl"}},
+	} {
+		ids := map[string]bool{}
+		for _, orgID := range []string{"", "staging"} {
+			raw := map[string]interface{}{"organization_id": orgID}
+			for k, value := range v.raw {
+				raw[k] = value
+			}
+
+			resourceData := schema.TestResourceDataRaw(test, v.resource.Schema, raw)
+			if err := v.resource.Read(resourceData, meta); err != nil {
+				test.Fatal(err)
+			}
+			ids[resourceData.Id()] = true
+		}
+
+		if len(ids) != 2 {
+			test.Errorf("Expected the same %v in different organizations to have different IDs", v.raw)
+		}
+	}
+}
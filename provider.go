@@ -1,11 +1,14 @@
 package main
 
 import (
+	"fmt"
 	"log"
+	"sync"
 
 	"github.com/jfcantu/threatstack-golang/threatstack"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 )
 
 // Provider - as required
@@ -14,27 +17,168 @@ func Provider() *schema.Provider {
 		Schema: map[string]*schema.Schema{
 			"api_key": {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				Description: "Threat Stack API key.",
-				DefaultFunc: schema.EnvDefaultFunc("THREATSTACK_API_KEY", nil),
 			},
 			"organization_id": {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				Description: "Threat Stack organization ID.",
-				DefaultFunc: schema.EnvDefaultFunc("THREATSTACK_ORG_ID", nil),
 			},
 			"user_id": {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				Description: "Threat Stack user ID.",
-				DefaultFunc: schema.EnvDefaultFunc("THREATSTACK_USER_ID", nil),
 			},
+			"profile": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Profile in the shared credentials file to read credentials from.",
+				DefaultFunc: schema.EnvDefaultFunc("THREATSTACK_PROFILE", ""),
+			},
+			"shared_credentials_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Path to the shared credentials file. Defaults to ~/.threatstack/credentials.",
+				DefaultFunc: schema.EnvDefaultFunc("THREATSTACK_SHARED_CREDENTIALS_FILE", ""),
+			},
+			"credential_process": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Command that prints credentials as JSON.",
+				DefaultFunc: schema.EnvDefaultFunc("THREATSTACK_CREDENTIAL_PROCESS", ""),
+			},
+			"proxy_url": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "URL of the HTTP proxy to reach the API through. Defaults to the HTTPS_PROXY environment variable.",
+				DefaultFunc: schema.EnvDefaultFunc("THREATSTACK_PROXY_URL", ""),
+			},
+			"ca_cert_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "Path to PEM CA certificates to trust in addition to the system's.",
+				DefaultFunc:   schema.EnvDefaultFunc("THREATSTACK_CA_CERT_FILE", ""),
+				ConflictsWith: []string{"ca_cert_pem"},
+			},
+			"ca_cert_pem": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "PEM CA certificates to trust in addition to the system's.",
+				ConflictsWith: []string{"ca_cert_file"},
+			},
+			"insecure_skip_verify": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Whether to skip verifying the API's TLS certificate. Insecure; prefer ca_cert_file or ca_cert_pem.",
+				DefaultFunc: schema.EnvDefaultFunc("THREATSTACK_INSECURE_SKIP_VERIFY", false),
+			},
+			"client_cert_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "Path to a PEM client certificate to present, e.g. to the proxy.",
+				DefaultFunc:   schema.EnvDefaultFunc("THREATSTACK_CLIENT_CERT_FILE", ""),
+				ConflictsWith: []string{"client_cert_pem"},
+			},
+			"client_key_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "Path to the PEM private key of client_cert_file.",
+				DefaultFunc:   schema.EnvDefaultFunc("THREATSTACK_CLIENT_KEY_FILE", ""),
+				ConflictsWith: []string{"client_key_pem"},
+			},
+			"client_cert_pem": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "PEM client certificate to present, e.g. to the proxy.",
+				ConflictsWith: []string{"client_cert_file"},
+			},
+			"client_key_pem": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				Description:   "PEM private key of client_cert_pem.",
+				ConflictsWith: []string{"client_key_file"},
+			},
+			"organization": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Credentials for other organizations that resources can use with organization_id.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Threat Stack organization ID.",
+						},
+						"api_key": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Sensitive:   true,
+							Description: "Threat Stack API key for the organization. Defaults to the provider's api_key.",
+						},
+						"user_id": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Threat Stack user ID for the organization. Defaults to the provider's user_id.",
+						},
+					},
+				},
+			},
+			"default_include_tag": ruleTagSchema(),
+			"default_exclude_tag": ruleTagSchema(),
+			"tag_check": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      lintSeverityOff,
+				Description:  "Whether to check at plan time that rule tags exist (off, warning or error).",
+				ValidateFunc: validation.StringInSlice(lintSeverities, false),
+			},
+			"lint": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Plan-time lint settings for host and file rules.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"ignore": {
+							Type:        schema.TypeSet,
+							Optional:    true,
+							Description: "Lint checks to skip.",
+							Elem: &schema.Schema{
+								Type:         schema.TypeString,
+								ValidateFunc: validation.StringInSlice(lintCheckNames(), false),
+							},
+						},
+						"severity": {
+							Type:        schema.TypeMap,
+							Optional:    true,
+							Description: "Severity (off, warning or error) by lint check name.",
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+		},
+		DataSourcesMap: map[string]*schema.Resource{
+			"threatstack_agents":          dataSourceAgents(),
+			"threatstack_alerts":          dataSourceAlerts(),
+			"threatstack_audit_logs":      dataSourceAuditLogs(),
+			"threatstack_falco_rules":     dataSourceFalcoRules(),
+			"threatstack_rule_pack":       dataSourceRulePack(),
+			"threatstack_sigma_rule":      dataSourceSigmaRule(),
+			"threatstack_tags":            dataSourceTags(),
+			"threatstack_vulnerabilities": dataSourceVulnerabilities(),
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"threatstack_ruleset":   resourceRuleset(),
-			"threatstack_host_rule": resourceHostRule(),
-			"threatstack_file_rule": resourceFileRule(),
+			"threatstack_alert_dismissal":           resourceAlertDismissal(),
+			"threatstack_rule":                      resourceRule(),
+			"threatstack_ruleset":                   resourceRuleset(),
+			"threatstack_ruleset_copy":              resourceRulesetCopy(),
+			"threatstack_ruleset_replica":           resourceRulesetReplica(),
+			"threatstack_host_rule":                 resourceHostRule(),
+			"threatstack_file_rule":                 resourceFileRule(),
+			"threatstack_vulnerability_suppression": resourceVulnerabilitySuppression(),
 		},
 	}
 
@@ -46,14 +190,285 @@ func Provider() *schema.Provider {
 }
 
 func providerConfigure(data *schema.ResourceData) (interface{}, error) {
-	config := Config{
-		APIKey:         data.Get("api_key").(string),
-		OrganizationID: data.Get("organization_id").(string),
-		UserID:         data.Get("user_id").(string),
+	transport := &transportConfig{
+		ProxyURL:           data.Get("proxy_url").(string),
+		CACertFile:         data.Get("ca_cert_file").(string),
+		CACertPEM:          data.Get("ca_cert_pem").(string),
+		InsecureSkipVerify: data.Get("insecure_skip_verify").(bool),
+		ClientCertFile:     data.Get("client_cert_file").(string),
+		ClientKeyFile:      data.Get("client_key_file").(string),
+		ClientCertPEM:      data.Get("client_cert_pem").(string),
+		ClientKeyPEM:       data.Get("client_key_pem").(string),
+	}
+	if err := transport.Install(); err != nil {
+		return nil, err
+	}
+
+	sources, process, processSource, err := providerCredentialSources(data)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := resolveCredentials(sources)
+	if err != nil {
+		return nil, err
+	}
+
+	var ignore []string
+	severity := map[string]string{}
+	if v, ok := data.GetOk("lint.0"); ok {
+		lint := v.(map[string]interface{})
+		for _, name := range lint["ignore"].(*schema.Set).List() {
+			ignore = append(ignore, name.(string))
+		}
+		for name, level := range lint["severity"].(map[string]interface{}) {
+			severity[name] = level.(string)
+		}
+	}
+
+	lint, err := newLintConfig(ignore, severity)
+	if err != nil {
+		return nil, err
 	}
 
 	log.Println("[INFO] Initializing Threat Stack client")
-	return config.Client()
+	client, err := config.Client()
+	if err != nil {
+		return nil, err
+	}
+
+	credentials := map[string]Config{}
+	for _, v := range data.Get("organization").([]interface{}) {
+		org := v.(map[string]interface{})
+		orgConfig := Config{
+			APIKey:         org["api_key"].(string),
+			OrganizationID: org["id"].(string),
+			UserID:         org["user_id"].(string),
+		}
+		if _, ok := credentials[orgConfig.OrganizationID]; ok || orgConfig.OrganizationID == config.OrganizationID {
+			return nil, fmt.Errorf("Organization %s is configured more than once", orgConfig.OrganizationID)
+		}
+		credentials[orgConfig.OrganizationID] = orgConfig
+	}
+
+	defaultTags := threatstack.NewTagSet()
+	defaultTags.Include = expandTags(data.Get("default_include_tag").(*schema.Set).List())
+	defaultTags.Exclude = expandTags(data.Get("default_exclude_tag").(*schema.Set).List())
+
+	return &providerMeta{
+		Client:        client,
+		config:        *config,
+		credentials:   credentials,
+		sources:       sources,
+		process:       process,
+		processSource: processSource,
+		Lint:          lint,
+		DefaultTags:   defaultTags,
+		TagCheck:      data.Get("tag_check").(string),
+	}, nil
+}
+
+// providerCredentialSources returns the sources of the provider's
+// credentials, in the order documented on resolveCredentials, along with the
+// credential process and its source if there is one. Sources are only read
+// up to the first one that sets any credentials, since resolveCredentials
+// never looks further; in particular, the credential process isn't run when
+// the provider arguments set credentials.
+func providerCredentialSources(data *schema.ResourceData) ([]*credentialSource, *credentialProcess, *credentialSource, error) {
+	sources := []*credentialSource{
+		{
+			Name: "provider arguments api_key, organization_id and user_id",
+			Config: Config{
+				APIKey:         data.Get("api_key").(string),
+				OrganizationID: data.Get("organization_id").(string),
+				UserID:         data.Get("user_id").(string),
+			},
+		},
+	}
+	if sources[0].Config != (Config{}) {
+		return sources, nil, nil, nil
+	}
+
+	// A credential process always returns complete credentials, so the
+	// remaining sources are never needed.
+	if command := data.Get("credential_process").(string); command != "" {
+		process := &credentialProcess{Command: command}
+		source, err := process.Source()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return append(sources, source), process, source, nil
+	}
+
+	env := envCredentialSource()
+	sources = append(sources, env)
+	if env.Config != (Config{}) {
+		return sources, nil, nil, nil
+	}
+
+	profile, err := profileCredentialSource(data.Get("shared_credentials_file").(string), data.Get("profile").(string))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return append(sources, profile), nil, nil, nil
+}
+
+// providerMeta is the meta value passed to resources and data sources.
+// Client is the client for the provider's own organization; clients for
+// other organizations are created on first use by OrgClient.
+type providerMeta struct {
+	Client      *threatstack.Client
+	Lint        *lintConfig
+	DefaultTags *threatstack.TagSet
+	TagCheck    string
+
+	config      Config
+	credentials map[string]Config
+	// sources, process and processSource, the process's entry in sources,
+	// are kept so that credentials from the credential process can be
+	// refreshed when they expire.
+	sources       []*credentialSource
+	process       *credentialProcess
+	processSource *credentialSource
+
+	mu       sync.Mutex
+	clients  map[string]*threatstack.Client
+	hostTags map[string]*hostTagsResult
+}
+
+type hostTagsResult struct {
+	tags []*threatstack.Tag
+	err  error
+}
+
+// orgKey returns the key that clients and host tags are cached under. The
+// provider's own organization is always "". m.mu must be held, since the
+// provider's organization can change when credentials are refreshed.
+func (m *providerMeta) orgKey(orgID string) string {
+	if orgID == m.config.OrganizationID {
+		return ""
+	}
+	return orgID
+}
+
+// refreshCredentials reruns the credential process if its credentials have
+// expired, and replaces every client. m.mu must be held.
+func (m *providerMeta) refreshCredentials() error {
+	if m.process == nil || !m.process.Expired() {
+		return nil
+	}
+
+	log.Println("[INFO] Refreshing expired Threat Stack credentials")
+	source, err := m.process.Source()
+	if err != nil {
+		return err
+	}
+
+	for i, v := range m.sources {
+		if v == m.processSource {
+			m.sources[i] = source
+		}
+	}
+	m.processSource = source
+
+	config, err := resolveCredentials(m.sources)
+	if err != nil {
+		return err
+	}
+
+	client, err := config.Client()
+	if err != nil {
+		return err
+	}
+
+	m.Client = client
+	m.config = *config
+	m.clients = nil
+
+	return nil
+}
+
+// IsOwnOrganization returns whether orgID is the provider's own
+// organization.
+func (m *providerMeta) IsOwnOrganization(orgID string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.orgKey(orgID) == ""
+}
+
+// OrgClient returns the client for an organization, or for the provider's
+// own organization if orgID is empty. Organizations without credentials in
+// an organization block use the provider's API key and user ID.
+func (m *providerMeta) OrgClient(orgID string) (*threatstack.Client, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.refreshCredentials(); err != nil {
+		return nil, err
+	}
+
+	key := m.orgKey(orgID)
+	if key == "" {
+		return m.Client, nil
+	}
+
+	if client, ok := m.clients[key]; ok {
+		return client, nil
+	}
+
+	config, ok := m.credentials[key]
+	if !ok {
+		config.OrganizationID = key
+	}
+	if config.APIKey == "" {
+		config.APIKey = m.config.APIKey
+	}
+	if config.UserID == "" {
+		config.UserID = m.config.UserID
+	}
+
+	log.Printf("[INFO] Initializing Threat Stack client for organization %s", key)
+	client, err := config.Client()
+	if err != nil {
+		return nil, fmt.Errorf("Error creating client for organization %s: %s", key, err.Error())
+	}
+
+	if m.clients == nil {
+		m.clients = map[string]*threatstack.Client{}
+	}
+	m.clients[key] = client
+
+	return client, nil
+}
+
+// HostTags returns the tags of every agent in an organization. They're
+// retrieved at most once per organization per provider run, since every rule
+// is checked against the same list.
+func (m *providerMeta) HostTags(orgID string) ([]*threatstack.Tag, error) {
+	client, err := m.OrgClient(orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := m.orgKey(orgID)
+	if v, ok := m.hostTags[key]; ok {
+		return v.tags, v.err
+	}
+
+	log.Println("[INFO] Retrieving agent tags")
+	tags, err := listHostTags(client)
+
+	if m.hostTags == nil {
+		m.hostTags = map[string]*hostTagsResult{}
+	}
+	m.hostTags[key] = &hostTagsResult{tags, err}
+
+	return tags, err
 }
 
 // Client creates a new client.
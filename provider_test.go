@@ -2,12 +2,15 @@ package main
 
 import (
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/jfcantu/threatstack-golang/threatstack"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -31,6 +34,39 @@ func TestProviderImpl(t *testing.T) {
 	var _ terraform.ResourceProvider = Provider()
 }
 
+// testAPINotFound is a testAPIClient response body that makes the request
+// fail with a 404.
+const testAPINotFound = "404 Not Found"
+
+// testAPIClient returns a client for a test server that serves each request
+// path and query from responses.
+func testAPIClient(test *testing.T, responses map[string]string) *threatstack.Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.RequestURI()]
+		if !ok {
+			test.Errorf("Unexpected request %s %s", r.Method, r.URL.RequestURI())
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if body == testAPINotFound {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		fmt.Fprint(w, body)
+	}))
+	test.Cleanup(server.Close)
+
+	client, err := threatstack.NewClient(&threatstack.Config{
+		BaseURL:        server.URL,
+		APIKey:         "key",
+		OrganizationID: "org",
+		UserID:         "user",
+	})
+	if err != nil {
+		test.Fatal(err)
+	}
+	return client
+}
+
 func testAccPreCheck(test *testing.T) {
 	if v := os.Getenv("THREATSTACK_API_KEY"); v == "" {
 		test.Fatal("THREATSTACK_API_KEY must be set for acceptance tests")
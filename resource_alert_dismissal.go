@@ -0,0 +1,122 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+// resourceAlertDismissal dismisses the matching alerts once, on create.
+// Dismissals can't be undone through the API, so every argument forces a new
+// dismissal and destroying one only removes it from state.
+func resourceAlertDismissal() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAlertDismissalCreate,
+		Read:   resourceAlertDismissalRead,
+		Delete: resourceAlertDismissalDelete,
+
+		Schema: map[string]*schema.Schema{
+			"organization_id": organizationIDSchema(),
+			"rule_id": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				AtLeastOneOf: []string{"rule_id", "ruleset_id"},
+			},
+			"ruleset_id": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				AtLeastOneOf: []string{"rule_id", "ruleset_id"},
+			},
+			"severity": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntBetween(1, 3),
+			},
+			"from": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+			},
+			"until": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+			},
+			"reason": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "OTHER",
+				ValidateFunc: validation.StringInSlice(alertDismissReasons, false),
+			},
+			"reason_text": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"alert_ids": &schema.Schema{
+				Type:     schema.TypeSet,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"dismissed_at": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceAlertDismissalCreate(resourceData *schema.ResourceData, meta interface{}) error {
+	client, err := resourceClient(resourceData, meta)
+	if err != nil {
+		return err
+	}
+
+	alerts, err := listAlerts(client, &alertQuery{
+		Status:    "active",
+		RuleID:    resourceData.Get("rule_id").(string),
+		RulesetID: resourceData.Get("ruleset_id").(string),
+		Severity:  resourceData.Get("severity").(int),
+		From:      resourceData.Get("from").(string),
+		Until:     resourceData.Get("until").(string),
+	})
+	if err != nil {
+		return err
+	}
+
+	var ids []string
+	for _, v := range alerts {
+		ids = append(ids, v.ID)
+	}
+
+	log.Printf("[INFO] Dismissing %d alerts", len(ids))
+
+	// Record whatever was dismissed even if a later batch fails, so that
+	// state reflects what actually happened.
+	dismissed, err := dismissAlerts(client, ids, resourceData.Get("reason").(string), resourceData.Get("reason_text").(string))
+	if len(dismissed) > 0 || err == nil {
+		resourceData.SetId(resource.PrefixedUniqueId("dismissal-"))
+		resourceData.Set("alert_ids", dismissed)
+		resourceData.Set("dismissed_at", time.Now().UTC().Format(time.RFC3339))
+	}
+
+	return err
+}
+
+func resourceAlertDismissalRead(resourceData *schema.ResourceData, meta interface{}) error {
+	return nil
+}
+
+func resourceAlertDismissalDelete(resourceData *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Removing alert dismissal %s from state; the alerts stay dismissed", resourceData.Id())
+	return nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/jfcantu/threatstack-golang/threatstack"
+)
+
+func TestAlertDismissalCreate(test *testing.T) {
+	var dismissals []*alertDismissal
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.RequestURI() == "/v2/alerts?ruleId=r1&status=active":
+			w.Write([]byte(`{"alerts": [
+				{"id": "al1", "ruleId": "r1", "rulesetId": "rs1"},
+				{"id": "al2", "ruleId": "r1", "rulesetId": "rs1"}
+			]}`))
+		case r.Method == "POST" && r.URL.Path == "/v2/alerts/dismiss":
+			body := new(alertDismissal)
+			if err := json.NewDecoder(r.Body).Decode(body); err != nil {
+				test.Error(err)
+			}
+			dismissals = append(dismissals, body)
+			w.Write([]byte(`{}`))
+		default:
+			test.Errorf("Unexpected request %s %s", r.Method, r.URL.RequestURI())
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := threatstack.NewClient(&threatstack.Config{BaseURL: server.URL, APIKey: "key", OrganizationID: "org", UserID: "user"})
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	resourceData := schema.TestResourceDataRaw(test, resourceAlertDismissal().Schema, map[string]interface{}{
+		"rule_id":     "r1",
+		"reason":      "MAINTENANCE",
+		"reason_text": "Noisy during the kernel upgrade",
+	})
+	if err := resourceAlertDismissalCreate(resourceData, &providerMeta{Client: client}); err != nil {
+		test.Fatal(err)
+	}
+
+	expected := []*alertDismissal{{
+		IDs:               []string{"al1", "al2"},
+		DismissReason:     "MAINTENANCE",
+		DismissReasonText: "Noisy during the kernel upgrade",
+	}}
+	if !reflect.DeepEqual(dismissals, expected) {
+		test.Errorf("Expected dismissals %#v, got %#v", expected, dismissals)
+	}
+
+	var ids []string
+	for _, v := range resourceData.Get("alert_ids").(*schema.Set).List() {
+		ids = append(ids, v.(string))
+	}
+	sort.Strings(ids)
+	if resourceData.Id() == "" || !reflect.DeepEqual(ids, []string{"al1", "al2"}) {
+		test.Errorf("Expected ID to be set and alert_ids [al1 al2], got %q and %v", resourceData.Id(), ids)
+	}
+}
@@ -1,6 +1,10 @@
 package main
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 	"github.com/jfcantu/threatstack-golang/threatstack"
@@ -12,8 +16,20 @@ func resourceFileRule() *schema.Resource {
 		Read:   resourceFileRuleRead,
 		Update: resourceFileRuleUpdate,
 		Delete: resourceFileRuleDelete,
+		Importer: &schema.ResourceImporter{
+			State: importStateOrganization(typedRuleImportState("file rule", func(rule threatstack.Rule) bool {
+				_, ok := rule.(*threatstack.FileRule)
+				return ok
+			})),
+		},
+		CustomizeDiff: customdiff.All(
+			customizeDiffLint(lintFileRuleDiff, "filter", "window", "threshold", "suppressions", "file_path"),
+			customizeDiffDefaultTags,
+			customizeDiffTagCheck,
+		),
 
 		Schema: map[string]*schema.Schema{
+			"organization_id": organizationIDSchema(),
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
@@ -58,6 +74,13 @@ func resourceFileRule() *schema.Resource {
 					},
 				},
 			},
+			"include_tag_all": ruleTagAllSchema(),
+			"exclude_tag_all": ruleTagAllSchema(),
+			"ignore_default_tags": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 			"title": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
@@ -101,6 +124,7 @@ func resourceFileRule() *schema.Resource {
 				Optional: true,
 				Default:  true,
 			},
+			"lint_ignore": lintIgnoreSchema(),
 			"file_path": &schema.Schema{
 				Type:     schema.TypeSet,
 				Required: true,
@@ -133,7 +157,10 @@ func resourceFileRule() *schema.Resource {
 }
 
 func resourceFileRuleCreate(resourceData *schema.ResourceData, meta interface{}) error {
-	client := meta.(*threatstack.Client)
+	client, err := resourceClient(resourceData, meta)
+	if err != nil {
+		return err
+	}
 
 	name := resourceData.Get("name").(string)
 	title := resourceData.Get("title").(string)
@@ -156,22 +183,7 @@ func resourceFileRuleCreate(resourceData *schema.ResourceData, meta interface{})
 	}
 
 	enabled := resourceData.Get("enabled").(bool)
-	tags := threatstack.NewTagSet()
-
-	for _, tag := range resourceData.Get("include_tag").(*schema.Set).List() {
-		tags.Include = append(tags.Include, &threatstack.Tag{
-			Source: tag.(map[string]interface{})["source"].(string),
-			Key:    tag.(map[string]interface{})["key"].(string),
-			Value:  tag.(map[string]interface{})["value"].(string),
-		})
-	}
-	for _, tag := range resourceData.Get("exclude_tag").(*schema.Set).List() {
-		tags.Exclude = append(tags.Exclude, &threatstack.Tag{
-			Source: tag.(map[string]interface{})["source"].(string),
-			Key:    tag.(map[string]interface{})["key"].(string),
-			Value:  tag.(map[string]interface{})["value"].(string),
-		})
-	}
+	tags := expandRuleTags(resourceData)
 
 	paths := []*threatstack.FilePath{}
 	for _, path := range resourceData.Get("file_path").(*schema.Set).List() {
@@ -219,54 +231,49 @@ func resourceFileRuleCreate(resourceData *schema.ResourceData, meta interface{})
 }
 
 func resourceFileRuleRead(resourceData *schema.ResourceData, meta interface{}) error {
-	client := meta.(*threatstack.Client)
+	client, err := resourceClient(resourceData, meta)
+	if err != nil {
+		return err
+	}
 
 	ruleset := resourceData.Get("ruleset").(string)
 	id := resourceData.Id()
 
 	resp, err := client.Rules.Get(ruleset, id)
 	if err != nil {
-		return nil
+		if strings.Contains(err.Error(), "404") {
+			resourceData.SetId("")
+			return nil
+		}
+		return err
 	}
 
-	var includeTags []map[string]interface{}
-	var excludeTags []map[string]interface{}
-
-	for _, v := range (*resp).(*threatstack.FileRule).GetTags().Include {
-		includeTags = append(includeTags, map[string]interface{}{
-			"source": v.Source,
-			"key":    v.Key,
-			"value":  v.Value,
-		})
+	rule, ok := (*resp).(*threatstack.FileRule)
+	if !ok {
+		return fmt.Errorf("Rule %s in ruleset %s is not a file rule", id, ruleset)
 	}
 
-	for _, v := range (*resp).(*threatstack.FileRule).GetTags().Exclude {
-		excludeTags = append(excludeTags, map[string]interface{}{
-			"source": v.Source,
-			"key":    v.Key,
-			"value":  v.Value,
-		})
-	}
-
-	resourceData.Set("name", (*resp).(*threatstack.FileRule).Name)
-	resourceData.Set("type", (*resp).(*threatstack.FileRule).Type)
-	resourceData.Set("title", (*resp).(*threatstack.FileRule).Title)
-	resourceData.Set("description", (*resp).(*threatstack.FileRule).Description)
-	resourceData.Set("severity", (*resp).(*threatstack.FileRule).Severity)
-	resourceData.Set("aggregate_fields", (*resp).(*threatstack.FileRule).AggregateFields)
-	resourceData.Set("filter", (*resp).(*threatstack.FileRule).Filter)
-	resourceData.Set("window", (*resp).(*threatstack.FileRule).Window)
-	resourceData.Set("suppressions", (*resp).(*threatstack.FileRule).Suppressions)
-	resourceData.Set("threshold", (*resp).(*threatstack.FileRule).Threshold)
-	resourceData.Set("enabled", (*resp).(*threatstack.FileRule).Enabled)
-	resourceData.Set("include_tag", includeTags)
-	resourceData.Set("exclude_tag", excludeTags)
+	resourceData.Set("name", rule.Name)
+	resourceData.Set("type", rule.Type)
+	resourceData.Set("title", rule.Title)
+	resourceData.Set("description", rule.Description)
+	resourceData.Set("severity", rule.Severity)
+	resourceData.Set("aggregate_fields", rule.AggregateFields)
+	resourceData.Set("filter", rule.Filter)
+	resourceData.Set("window", rule.Window)
+	resourceData.Set("suppressions", rule.Suppressions)
+	resourceData.Set("threshold", rule.Threshold)
+	resourceData.Set("enabled", rule.Enabled)
+	setRuleTags(resourceData, rule.GetTags(), meta)
 
 	return nil
 }
 
 func resourceFileRuleUpdate(resourceData *schema.ResourceData, meta interface{}) error {
-	client := meta.(*threatstack.Client)
+	client, err := resourceClient(resourceData, meta)
+	if err != nil {
+		return err
+	}
 
 	id := resourceData.Id()
 	name := resourceData.Get("name").(string)
@@ -290,22 +297,7 @@ func resourceFileRuleUpdate(resourceData *schema.ResourceData, meta interface{})
 	}
 
 	enabled := resourceData.Get("enabled").(bool)
-	tags := threatstack.NewTagSet()
-
-	for _, tag := range resourceData.Get("include_tag").(*schema.Set).List() {
-		tags.Include = append(tags.Include, &threatstack.Tag{
-			Source: tag.(map[string]interface{})["source"].(string),
-			Key:    tag.(map[string]interface{})["key"].(string),
-			Value:  tag.(map[string]interface{})["value"].(string),
-		})
-	}
-	for _, tag := range resourceData.Get("exclude_tag").(*schema.Set).List() {
-		tags.Exclude = append(tags.Exclude, &threatstack.Tag{
-			Source: tag.(map[string]interface{})["source"].(string),
-			Key:    tag.(map[string]interface{})["key"].(string),
-			Value:  tag.(map[string]interface{})["value"].(string),
-		})
-	}
+	tags := expandRuleTags(resourceData)
 
 	paths := []*threatstack.FilePath{}
 	for _, path := range resourceData.Get("file_path").(*schema.Set).List() {
@@ -325,7 +317,7 @@ func resourceFileRuleUpdate(resourceData *schema.ResourceData, meta interface{})
 		monitorEvents = append(monitorEvents, v.(string))
 	}
 
-	_, err := client.Rules.Update(
+	_, err = client.Rules.Update(
 		ruleset,
 		id,
 		&threatstack.FileRule{
@@ -353,12 +345,15 @@ func resourceFileRuleUpdate(resourceData *schema.ResourceData, meta interface{})
 }
 
 func resourceFileRuleDelete(resourceData *schema.ResourceData, meta interface{}) error {
-	client := meta.(*threatstack.Client)
+	client, err := resourceClient(resourceData, meta)
+	if err != nil {
+		return err
+	}
 
 	id := resourceData.Id()
 	ruleset := resourceData.Get("ruleset").(string)
 
-	err := client.Rules.Delete(ruleset, id)
+	err = client.Rules.Delete(ruleset, id)
 	if err != nil {
 		return nil
 	}
@@ -1,6 +1,10 @@
 package main
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 	"github.com/jfcantu/threatstack-golang/threatstack"
@@ -12,8 +16,20 @@ func resourceHostRule() *schema.Resource {
 		Read:   resourceHostRuleRead,
 		Update: resourceHostRuleUpdate,
 		Delete: resourceHostRuleDelete,
+		Importer: &schema.ResourceImporter{
+			State: importStateOrganization(typedRuleImportState("host rule", func(rule threatstack.Rule) bool {
+				r, ok := rule.(*threatstack.HostRule)
+				return ok && r.Type == "Host"
+			})),
+		},
+		CustomizeDiff: customdiff.All(
+			customizeDiffLint(lintHostRuleDiff, "filter", "window", "threshold", "suppressions"),
+			customizeDiffDefaultTags,
+			customizeDiffTagCheck,
+		),
 
 		Schema: map[string]*schema.Schema{
+			"organization_id": organizationIDSchema(),
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
@@ -58,6 +74,13 @@ func resourceHostRule() *schema.Resource {
 					},
 				},
 			},
+			"include_tag_all": ruleTagAllSchema(),
+			"exclude_tag_all": ruleTagAllSchema(),
+			"ignore_default_tags": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 			"title": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
@@ -101,12 +124,16 @@ func resourceHostRule() *schema.Resource {
 				Optional: true,
 				Default:  true,
 			},
+			"lint_ignore": lintIgnoreSchema(),
 		},
 	}
 }
 
 func resourceHostRuleCreate(resourceData *schema.ResourceData, meta interface{}) error {
-	client := meta.(*threatstack.Client)
+	client, err := resourceClient(resourceData, meta)
+	if err != nil {
+		return err
+	}
 
 	name := resourceData.Get("name").(string)
 	title := resourceData.Get("title").(string)
@@ -128,22 +155,7 @@ func resourceHostRuleCreate(resourceData *schema.ResourceData, meta interface{})
 		suppressions = append(suppressions, v.(string))
 	}
 	enabled := resourceData.Get("enabled").(bool)
-	tags := threatstack.NewTagSet()
-
-	for _, tag := range resourceData.Get("include_tag").(*schema.Set).List() {
-		tags.Include = append(tags.Include, &threatstack.Tag{
-			Source: tag.(map[string]interface{})["source"].(string),
-			Key:    tag.(map[string]interface{})["key"].(string),
-			Value:  tag.(map[string]interface{})["value"].(string),
-		})
-	}
-	for _, tag := range resourceData.Get("exclude_tag").(*schema.Set).List() {
-		tags.Exclude = append(tags.Exclude, &threatstack.Tag{
-			Source: tag.(map[string]interface{})["source"].(string),
-			Key:    tag.(map[string]interface{})["key"].(string),
-			Value:  tag.(map[string]interface{})["value"].(string),
-		})
-	}
+	tags := expandRuleTags(resourceData)
 
 	rule, err := client.Rules.Create(
 		ruleset,
@@ -170,54 +182,51 @@ func resourceHostRuleCreate(resourceData *schema.ResourceData, meta interface{})
 }
 
 func resourceHostRuleRead(resourceData *schema.ResourceData, meta interface{}) error {
-	client := meta.(*threatstack.Client)
+	client, err := resourceClient(resourceData, meta)
+	if err != nil {
+		return err
+	}
 
 	ruleset := resourceData.Get("ruleset").(string)
 	id := resourceData.Id()
 
 	resp, err := client.Rules.Get(ruleset, id)
 	if err != nil {
-		return nil
+		if strings.Contains(err.Error(), "404") {
+			resourceData.SetId("")
+			return nil
+		}
+		return err
 	}
 
-	var includeTags []map[string]interface{}
-	var excludeTags []map[string]interface{}
-
-	for _, v := range (*resp).(*threatstack.HostRule).GetTags().Include {
-		includeTags = append(includeTags, map[string]interface{}{
-			"source": v.Source,
-			"key":    v.Key,
-			"value":  v.Value,
-		})
+	// The client library decodes CloudTrail, Winsec and ThreatIntel rules as
+	// host rules too.
+	rule, ok := (*resp).(*threatstack.HostRule)
+	if !ok || rule.Type != "Host" {
+		return fmt.Errorf("Rule %s in ruleset %s is not a host rule", id, ruleset)
 	}
 
-	for _, v := range (*resp).(*threatstack.HostRule).GetTags().Exclude {
-		excludeTags = append(excludeTags, map[string]interface{}{
-			"source": v.Source,
-			"key":    v.Key,
-			"value":  v.Value,
-		})
-	}
-
-	resourceData.Set("name", (*resp).(*threatstack.HostRule).Name)
-	resourceData.Set("type", (*resp).(*threatstack.HostRule).Type)
-	resourceData.Set("title", (*resp).(*threatstack.HostRule).Title)
-	resourceData.Set("description", (*resp).(*threatstack.HostRule).Description)
-	resourceData.Set("severity", (*resp).(*threatstack.HostRule).Severity)
-	resourceData.Set("aggregate_fields", (*resp).(*threatstack.HostRule).AggregateFields)
-	resourceData.Set("filter", (*resp).(*threatstack.HostRule).Filter)
-	resourceData.Set("window", (*resp).(*threatstack.HostRule).Window)
-	resourceData.Set("suppressions", (*resp).(*threatstack.HostRule).Suppressions)
-	resourceData.Set("threshold", (*resp).(*threatstack.HostRule).Threshold)
-	resourceData.Set("enabled", (*resp).(*threatstack.HostRule).Enabled)
-	resourceData.Set("include_tag", includeTags)
-	resourceData.Set("exclude_tag", excludeTags)
+	resourceData.Set("name", rule.Name)
+	resourceData.Set("type", rule.Type)
+	resourceData.Set("title", rule.Title)
+	resourceData.Set("description", rule.Description)
+	resourceData.Set("severity", rule.Severity)
+	resourceData.Set("aggregate_fields", rule.AggregateFields)
+	resourceData.Set("filter", rule.Filter)
+	resourceData.Set("window", rule.Window)
+	resourceData.Set("suppressions", rule.Suppressions)
+	resourceData.Set("threshold", rule.Threshold)
+	resourceData.Set("enabled", rule.Enabled)
+	setRuleTags(resourceData, rule.GetTags(), meta)
 
 	return nil
 }
 
 func resourceHostRuleUpdate(resourceData *schema.ResourceData, meta interface{}) error {
-	client := meta.(*threatstack.Client)
+	client, err := resourceClient(resourceData, meta)
+	if err != nil {
+		return err
+	}
 
 	id := resourceData.Id()
 	name := resourceData.Get("name").(string)
@@ -241,24 +250,9 @@ func resourceHostRuleUpdate(resourceData *schema.ResourceData, meta interface{})
 	}
 
 	enabled := resourceData.Get("enabled").(bool)
-	tags := threatstack.NewTagSet()
+	tags := expandRuleTags(resourceData)
 
-	for _, tag := range resourceData.Get("include_tag").(*schema.Set).List() {
-		tags.Include = append(tags.Include, &threatstack.Tag{
-			Source: tag.(map[string]interface{})["source"].(string),
-			Key:    tag.(map[string]interface{})["key"].(string),
-			Value:  tag.(map[string]interface{})["value"].(string),
-		})
-	}
-	for _, tag := range resourceData.Get("exclude_tag").(*schema.Set).List() {
-		tags.Exclude = append(tags.Exclude, &threatstack.Tag{
-			Source: tag.(map[string]interface{})["source"].(string),
-			Key:    tag.(map[string]interface{})["key"].(string),
-			Value:  tag.(map[string]interface{})["value"].(string),
-		})
-	}
-
-	_, err := client.Rules.Update(
+	_, err = client.Rules.Update(
 		ruleset,
 		id,
 		&threatstack.HostRule{
@@ -284,12 +278,15 @@ func resourceHostRuleUpdate(resourceData *schema.ResourceData, meta interface{})
 }
 
 func resourceHostRuleDelete(resourceData *schema.ResourceData, meta interface{}) error {
-	client := meta.(*threatstack.Client)
+	client, err := resourceClient(resourceData, meta)
+	if err != nil {
+		return err
+	}
 
 	id := resourceData.Id()
 	ruleset := resourceData.Get("ruleset").(string)
 
-	err := client.Rules.Delete(ruleset, id)
+	err = client.Rules.Delete(ruleset, id)
 	if err != nil {
 		return nil
 	}
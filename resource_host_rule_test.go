@@ -3,9 +3,11 @@ package main
 import (
 	"fmt"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform/helper/acctest"
 )
 
@@ -194,3 +196,57 @@ resource "threatstack_host_rule" "test" {
 }
 `, name, title, desc, severity)
 }
+
+func TestResourceHostRuleImportStateBadID(test *testing.T) {
+	meta := &providerMeta{Client: testAPIClient(test, map[string]string{
+		"/v2/rulesets/rs1/rules/host": `{"id": "host", "type": "Host", "name": "host"}`,
+		"/v2/rules/host/tags":         `{"inclusion": [], "exclusion": []}`,
+		"/v2/rulesets/rs1/rules/file": `{"id": "file", "type": "File", "name": "file"}`,
+		"/v2/rules/file/tags":         `{"inclusion": [], "exclusion": []}`,
+		"/v2/rulesets/rs1/rules/ct":   `{"id": "ct", "type": "CloudTrail", "name": "ct"}`,
+		"/v2/rules/ct/tags":           `{"inclusion": [], "exclusion": []}`,
+		"/v2/rulesets/rs1/rules/gone": testAPINotFound,
+	})}
+	importer := resourceHostRule().Importer.State
+
+	for _, v := range []struct {
+		id      string
+		message string
+	}{
+		{"rs1/host", ""},
+		{"rs1/file", "is not a host rule"},
+		{"rs1/ct", "is not a host rule"},
+		{"rs1/gone", "404"},
+		{"host", "expected <ruleset ID>/<rule ID>"},
+	} {
+		resourceData := schema.TestResourceDataRaw(test, resourceHostRule().Schema, map[string]interface{}{})
+		resourceData.SetId(v.id)
+
+		_, err := importer(resourceData, meta)
+		if v.message == "" {
+			if err != nil {
+				test.Errorf("Expected %s to import, got %s", v.id, err)
+			}
+		} else if err == nil || !strings.Contains(err.Error(), v.message) {
+			test.Errorf("Expected an error mentioning %q importing %s, got %v", v.message, v.id, err)
+		}
+	}
+}
+
+func TestResourceHostRuleReadNotFound(test *testing.T) {
+	meta := &providerMeta{Client: testAPIClient(test, map[string]string{
+		"/v2/rulesets/rs1/rules/gone": testAPINotFound,
+	})}
+
+	resourceData := schema.TestResourceDataRaw(test, resourceHostRule().Schema, map[string]interface{}{
+		"ruleset": "rs1",
+	})
+	resourceData.SetId("gone")
+
+	if err := resourceHostRuleRead(resourceData, meta); err != nil {
+		test.Fatal(err)
+	}
+	if resourceData.Id() != "" {
+		test.Errorf("Expected a deleted rule to be removed from state, got ID %q", resourceData.Id())
+	}
+}
@@ -1,10 +1,261 @@
 package main
 
 import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/structure"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/jfcantu/threatstack-golang/threatstack"
 )
 
+// ruleServerFields are set by the API and never part of a rule definition.
+var ruleServerFields = []string{
+	"id",
+	"rulesetId",
+	"type",
+	"createdAt",
+	"updatedAt",
+}
+
+func resourceRule() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceRuleCreate,
+		Read:   resourceRuleRead,
+		Update: resourceRuleUpdate,
+		Delete: resourceRuleDelete,
+		Importer: &schema.ResourceImporter{
+			State: importStateOrganization(resourceRuleImportState),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"organization_id": organizationIDSchema(),
+			"ruleset": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"type": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"definition": &schema.Schema{
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateFunc:     validation.StringIsJSON,
+				DiffSuppressFunc: structure.SuppressJsonDiff,
+				StateFunc: func(v interface{}) string {
+					normalized, _ := structure.NormalizeJsonString(v)
+					return normalized
+				},
+			},
+		},
+	}
+}
+
+func resourceRuleCreate(resourceData *schema.ResourceData, meta interface{}) error {
+	client, err := resourceClient(resourceData, meta)
+	if err != nil {
+		return err
+	}
+
+	ruleset := resourceData.Get("ruleset").(string)
+
+	body, err := ruleDefinitionBody(resourceData)
+	if err != nil {
+		return err
+	}
+
+	raw, err := client.CreateObject(fmt.Sprintf("rulesets/%s/rules", ruleset), nil, body)
+	if err != nil {
+		return err
+	}
+
+	resp := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return err
+	}
+
+	id, ok := resp["id"].(string)
+	if !ok || id == "" {
+		return fmt.Errorf("Rules API did not return an ID for the new rule: %s", string(raw))
+	}
+
+	resourceData.SetId(id)
+	return resourceRuleRead(resourceData, meta)
+}
+
+func resourceRuleRead(resourceData *schema.ResourceData, meta interface{}) error {
+	client, err := resourceClient(resourceData, meta)
+	if err != nil {
+		return err
+	}
+
+	ruleset := resourceData.Get("ruleset").(string)
+	id := resourceData.Id()
+
+	raw, err := client.GetObject(fmt.Sprintf("rulesets/%s/rules/%s", ruleset, id), nil)
+	if err != nil {
+		if strings.Contains(err.Error(), "404") {
+			resourceData.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	resp := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return err
+	}
+
+	// Only report the fields that were configured, so that defaults filled
+	// in by the API don't show up as a permanent diff. When nothing has been
+	// configured yet (e.g. on import) everything is reported.
+	configured := map[string]interface{}{}
+	if v := resourceData.Get("definition").(string); v != "" {
+		if err := json.Unmarshal([]byte(v), &configured); err != nil {
+			return err
+		}
+	}
+
+	definition := map[string]interface{}{}
+	for k, v := range resp {
+		if _, ok := configured[k]; ok || len(configured) == 0 {
+			definition[k] = v
+		}
+	}
+	for _, k := range ruleServerFields {
+		delete(definition, k)
+	}
+
+	normalized, err := structure.FlattenJsonToString(definition)
+	if err != nil {
+		return err
+	}
+
+	resourceData.Set("type", resp["type"])
+	resourceData.Set("definition", normalized)
+
+	return nil
+}
+
+func resourceRuleUpdate(resourceData *schema.ResourceData, meta interface{}) error {
+	client, err := resourceClient(resourceData, meta)
+	if err != nil {
+		return err
+	}
+
+	ruleset := resourceData.Get("ruleset").(string)
+	id := resourceData.Id()
+
+	body, err := ruleDefinitionBody(resourceData)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.UpdateObject(fmt.Sprintf("rulesets/%s/rules/%s", ruleset, id), nil, body)
+	if err != nil {
+		return err
+	}
+
+	return resourceRuleRead(resourceData, meta)
+}
+
+func resourceRuleDelete(resourceData *schema.ResourceData, meta interface{}) error {
+	client, err := resourceClient(resourceData, meta)
+	if err != nil {
+		return err
+	}
+
+	ruleset := resourceData.Get("ruleset").(string)
+	id := resourceData.Id()
+
+	return client.DeleteObject(fmt.Sprintf("rulesets/%s/rules/%s", ruleset, id), nil)
+}
+
+// splitRuleImportID splits a "<ruleset ID>/<rule ID>" import ID, since rules
+// can only be retrieved through their ruleset.
+func splitRuleImportID(resourceData *schema.ResourceData) error {
+	parts := strings.SplitN(resourceData.Id(), "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return fmt.Errorf("Unexpected import ID %q, expected <ruleset ID>/<rule ID>", resourceData.Id())
+	}
+
+	resourceData.Set("ruleset", parts[0])
+	resourceData.SetId(parts[1])
+
+	return nil
+}
+
+// resourceRuleImportState imports a rule of any type, checking that it
+// exists.
+func resourceRuleImportState(resourceData *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if err := splitRuleImportID(resourceData); err != nil {
+		return nil, err
+	}
+
+	client, err := resourceClient(resourceData, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	ruleset := resourceData.Get("ruleset").(string)
+	if _, err := client.GetObject(fmt.Sprintf("rulesets/%s/rules/%s", ruleset, resourceData.Id()), nil); err != nil {
+		return nil, fmt.Errorf("Error importing rule %s from ruleset %s: %s", resourceData.Id(), ruleset, err.Error())
+	}
+
+	return []*schema.ResourceData{resourceData}, nil
+}
+
+// typedRuleImportState returns an importer for threatstack_host_rule or
+// threatstack_file_rule, which checks that the rule exists and is of the
+// resource's type.
+func typedRuleImportState(typeName string, isType func(threatstack.Rule) bool) schema.StateFunc {
+	return func(resourceData *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+		if err := splitRuleImportID(resourceData); err != nil {
+			return nil, err
+		}
+
+		client, err := resourceClient(resourceData, meta)
+		if err != nil {
+			return nil, err
+		}
+
+		ruleset := resourceData.Get("ruleset").(string)
+		rule, err := client.Rules.Get(ruleset, resourceData.Id())
+		if err != nil {
+			return nil, fmt.Errorf("Error importing rule %s from ruleset %s: %s", resourceData.Id(), ruleset, err.Error())
+		}
+		if !isType(*rule) {
+			return nil, fmt.Errorf("Rule %s in ruleset %s is not a %s", resourceData.Id(), ruleset, typeName)
+		}
+
+		return []*schema.ResourceData{resourceData}, nil
+	}
+}
+
+// ruleDefinitionBody builds the request body for the rules API from the
+// configured type and JSON definition.
+func ruleDefinitionBody(resourceData *schema.ResourceData) (map[string]interface{}, error) {
+	body, err := structure.ExpandJsonFromString(resourceData.Get("definition").(string))
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing rule definition: %s", err.Error())
+	}
+
+	for _, k := range ruleServerFields {
+		if _, ok := body[k]; ok {
+			return nil, fmt.Errorf("Rule definition must not contain %q", k)
+		}
+	}
+
+	body["type"] = resourceData.Get("type").(string)
+
+	return body, nil
+}
+
 func validateRuleWindow() schema.SchemaValidateFunc {
 	return validation.IntInSlice(getValidRuleWindows())
 }
@@ -20,6 +271,10 @@ func getValidRuleWindows() []int {
 	}
 }
 
+func validateRuleThreshold() schema.SchemaValidateFunc {
+	return validation.IntInSlice(getValidRuleThresholds())
+}
+
 func getValidRuleThresholds() []int {
 	return []int{
 		1,
@@ -30,3 +285,38 @@ func getValidRuleThresholds() []int {
 		60,
 	}
 }
+
+// copyRule returns a copy of a rule with its server-assigned fields cleared,
+// suitable for creating in another ruleset.
+func copyRule(rule threatstack.Rule) (threatstack.Rule, error) {
+	switch r := rule.(type) {
+	case *threatstack.HostRule:
+		c := *r
+		c.ID, c.RulesetID, c.CreatedAt, c.UpdatedAt = "", "", "", ""
+		c.Tags = copyTagSet(r.Tags)
+		return &c, nil
+	case *threatstack.FileRule:
+		c := *r
+		c.ID, c.RulesetID, c.CreatedAt, c.UpdatedAt = "", "", "", ""
+		c.Tags = copyTagSet(r.Tags)
+		return &c, nil
+	default:
+		return nil, fmt.Errorf("Unsupported rule type %T", rule)
+	}
+}
+
+func copyTagSet(tags *threatstack.TagSet) *threatstack.TagSet {
+	ret := threatstack.NewTagSet()
+	if tags == nil {
+		return ret
+	}
+
+	for _, v := range tags.Include {
+		ret.Include = append(ret.Include, &threatstack.Tag{Source: v.Source, Key: v.Key, Value: v.Value})
+	}
+	for _, v := range tags.Exclude {
+		ret.Exclude = append(ret.Exclude, &threatstack.Tag{Source: v.Source, Key: v.Key, Value: v.Value})
+	}
+
+	return ret
+}
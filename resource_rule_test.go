@@ -4,13 +4,73 @@ import (
 	"fmt"
 	"math/rand"
 	"strings"
+	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
 	"github.com/hashicorp/terraform/helper/acctest"
-	"github.com/jfcantu/threatstack-golang/threatstack"
 )
 
+func init() {
+	resource.AddTestSweepers("threatstack_rule", &resource.Sweeper{
+		Name: "threatstack_rule",
+		F:    sweepRulesets,
+	})
+}
+
+func TestAccThreatstackRule_basic(test *testing.T) {
+	testRuleName := fmt.Sprintf("tf%s", acctest.RandString(5))
+
+	resource.Test(test, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(test) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckThreatstackRulesetDestroyed,
+		Steps: []resource.TestStep{
+			// Step 1: Create rule from JSON
+			{
+				Config: fmt.Sprintf("%s\n%s",
+					testAccBasicRule(testRuleName, 86400),
+					testAccThreatstackRuleTestRuleset(),
+				),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckThreatstackRuleExists("threatstack_rule.test"),
+					resource.TestCheckResourceAttr("threatstack_rule.test", "type", "Host"),
+					resource.TestCheckResourceAttrSet("threatstack_rule.test", "definition"),
+				),
+			},
+			// Step 2: Change window
+			{
+				Config: fmt.Sprintf("%s\n%s",
+					testAccBasicRule(testRuleName, 3600),
+					testAccThreatstackRuleTestRuleset(),
+				),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckThreatstackRuleExists("threatstack_rule.test"),
+					resource.TestCheckResourceAttr("threatstack_rule.test", "type", "Host"),
+				),
+			},
+		},
+	})
+}
+
+func testAccBasicRule(name string, window int) string {
+	return fmt.Sprintf(`
+resource "threatstack_rule" "test" {
+	ruleset = threatstack_ruleset.test.id
+	type = "Host"
+	definition = jsonencode({
+		name = "%s"
+		title = "TEST"
+		severityOfAlerts = 1
+		filter = "event_type = \"host\""
+		window = %d
+		threshold = 1
+		enabled = true
+	})
+}
+`, name, window)
+}
+
 type testRuleData struct {
 	Name            string
 	Title           string
@@ -41,7 +101,7 @@ func initializeTestRuleData() *testRuleData {
 func testAccCheckThreatstackRuleExists(name string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 
-		cli := testAccProvider.Meta().(*threatstack.Client)
+		cli := testAccProvider.Meta().(*providerMeta).Client
 
 		res, ok := s.RootModule().Resources[name]
 		if !ok {
@@ -61,7 +121,7 @@ func testAccCheckThreatstackRuleExists(name string) resource.TestCheckFunc {
 }
 
 func testAccCheckThreatstackRuleDestroyed(s *terraform.State) error {
-	cli := testAccProvider.Meta().(*threatstack.Client)
+	cli := testAccProvider.Meta().(*providerMeta).Client
 
 	for _, res := range s.RootModule().Resources {
 		if res.Type != "threatstack_ruleset" {
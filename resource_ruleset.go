@@ -1,6 +1,8 @@
 package main
 
 import (
+	"strings"
+
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/jfcantu/threatstack-golang/threatstack"
 )
@@ -11,8 +13,12 @@ func resourceRuleset() *schema.Resource {
 		Read:   resourceRulesetRead,
 		Update: resourceRulesetUpdate,
 		Delete: resourceRulesetDelete,
+		Importer: &schema.ResourceImporter{
+			State: importStateOrganization(schema.ImportStatePassthrough),
+		},
 
 		Schema: map[string]*schema.Schema{
+			"organization_id": organizationIDSchema(),
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
@@ -26,7 +32,10 @@ func resourceRuleset() *schema.Resource {
 }
 
 func resourceRulesetCreate(resourceData *schema.ResourceData, meta interface{}) error {
-	client := meta.(*threatstack.Client)
+	client, err := resourceClient(resourceData, meta)
+	if err != nil {
+		return err
+	}
 
 	name := resourceData.Get("name").(string)
 	desc := resourceData.Get("description").(string)
@@ -46,11 +55,18 @@ func resourceRulesetCreate(resourceData *schema.ResourceData, meta interface{})
 }
 
 func resourceRulesetRead(resourceData *schema.ResourceData, meta interface{}) error {
-	client := meta.(*threatstack.Client)
+	client, err := resourceClient(resourceData, meta)
+	if err != nil {
+		return err
+	}
 
 	data, err := client.Rulesets.Get(resourceData.Id())
 	if err != nil {
-		return nil
+		if strings.Contains(err.Error(), "404") {
+			resourceData.SetId("")
+			return nil
+		}
+		return err
 	}
 
 	resourceData.Set("name", data.Name)
@@ -60,7 +76,10 @@ func resourceRulesetRead(resourceData *schema.ResourceData, meta interface{}) er
 }
 
 func resourceRulesetUpdate(resourceData *schema.ResourceData, meta interface{}) error {
-	client := meta.(*threatstack.Client)
+	client, err := resourceClient(resourceData, meta)
+	if err != nil {
+		return err
+	}
 
 	id := resourceData.Id()
 	name := resourceData.Get("name").(string)
@@ -86,11 +105,14 @@ func resourceRulesetUpdate(resourceData *schema.ResourceData, meta interface{})
 }
 
 func resourceRulesetDelete(resourceData *schema.ResourceData, meta interface{}) error {
-	client := meta.(*threatstack.Client)
+	client, err := resourceClient(resourceData, meta)
+	if err != nil {
+		return err
+	}
 
 	id := resourceData.Id()
 
-	err := client.Rulesets.Delete(id)
+	err = client.Rulesets.Delete(id)
 	if err != nil {
 		return nil
 	}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/jfcantu/threatstack-golang/threatstack"
+)
+
+func resourceRulesetCopy() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceRulesetCopyCreate,
+		Read:   resourceRulesetCopyRead,
+		Update: resourceRulesetCopyUpdate,
+		Delete: resourceRulesetCopyDelete,
+
+		Schema: map[string]*schema.Schema{
+			"organization_id": organizationIDSchema(),
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"description": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"source_ruleset_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"rule_ids": &schema.Schema{
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func resourceRulesetCopyCreate(resourceData *schema.ResourceData, meta interface{}) error {
+	client, err := resourceClient(resourceData, meta)
+	if err != nil {
+		return err
+	}
+
+	name := resourceData.Get("name").(string)
+	desc := resourceData.Get("description").(string)
+	sourceID := resourceData.Get("source_ruleset_id").(string)
+
+	source, err := client.Rulesets.Get(sourceID)
+	if err != nil {
+		return fmt.Errorf("Error retrieving source ruleset %s: %s", sourceID, err.Error())
+	}
+
+	ruleset, err := client.Rulesets.Create(
+		&threatstack.Ruleset{
+			Name:        name,
+			Description: desc,
+			RuleIDs:     []string{},
+		})
+	if err != nil {
+		return err
+	}
+
+	resourceData.SetId(ruleset.ID)
+
+	ruleIDs := map[string]string{}
+	for _, id := range source.RuleIDs {
+		rule, err := client.Rules.Get(sourceID, id)
+		if err != nil {
+			resourceData.Set("rule_ids", ruleIDs)
+			return fmt.Errorf("Error retrieving rule %s from ruleset %s: %s", id, sourceID, err.Error())
+		}
+
+		newRule, err := copyRule(*rule)
+		if err != nil {
+			resourceData.Set("rule_ids", ruleIDs)
+			return err
+		}
+
+		log.Printf("[DEBUG] Copying rule %s into ruleset %s", id, ruleset.ID)
+
+		created, err := client.Rules.Create(ruleset.ID, newRule)
+		if err != nil {
+			resourceData.Set("rule_ids", ruleIDs)
+			return fmt.Errorf("Error copying rule %s: %s", id, err.Error())
+		}
+
+		ruleIDs[id] = (*created).GetID()
+	}
+
+	resourceData.Set("rule_ids", ruleIDs)
+	return resourceRulesetCopyRead(resourceData, meta)
+}
+
+func resourceRulesetCopyRead(resourceData *schema.ResourceData, meta interface{}) error {
+	client, err := resourceClient(resourceData, meta)
+	if err != nil {
+		return err
+	}
+
+	data, err := client.Rulesets.Get(resourceData.Id())
+	if err != nil {
+		if strings.Contains(err.Error(), "404") {
+			resourceData.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	current := map[string]bool{}
+	for _, id := range data.RuleIDs {
+		current[id] = true
+	}
+
+	// Drop copies that have since been deleted from the ruleset, so the
+	// mapping only ever refers to rules that actually exist.
+	ruleIDs := map[string]string{}
+	for k, v := range resourceData.Get("rule_ids").(map[string]interface{}) {
+		if current[v.(string)] {
+			ruleIDs[k] = v.(string)
+		}
+	}
+
+	resourceData.Set("name", data.Name)
+	resourceData.Set("description", data.Description)
+	resourceData.Set("rule_ids", ruleIDs)
+
+	return nil
+}
+
+func resourceRulesetCopyUpdate(resourceData *schema.ResourceData, meta interface{}) error {
+	if err := resourceRulesetUpdate(resourceData, meta); err != nil {
+		return err
+	}
+
+	return resourceRulesetCopyRead(resourceData, meta)
+}
+
+func resourceRulesetCopyDelete(resourceData *schema.ResourceData, meta interface{}) error {
+	return resourceRulesetDelete(resourceData, meta)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/hashicorp/terraform/helper/acctest"
+)
+
+func init() {
+	resource.AddTestSweepers("threatstack_ruleset_copy", &resource.Sweeper{
+		Name: "threatstack_ruleset_copy",
+		F:    sweepRulesets,
+	})
+}
+
+func TestAccThreatstackRulesetCopy_basic(test *testing.T) {
+	testRulesetName := fmt.Sprintf("tf%s", acctest.RandString(5))
+	testCopyName := fmt.Sprintf("tf%s", acctest.RandString(5))
+	testCopyName2 := fmt.Sprintf("tf%s", acctest.RandString(5))
+	testRuleName := fmt.Sprintf("tf%s", acctest.RandString(5))
+
+	resource.Test(test, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(test) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckThreatstackRulesetDestroyed,
+		Steps: []resource.TestStep{
+			// Step 1: Copy ruleset with one rule
+			{
+				Config: testAccThreatstackRulesetCopy(testRulesetName, testCopyName, testRuleName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckThreatstackRulesetExists("threatstack_ruleset_copy.test"),
+					resource.TestCheckResourceAttr("threatstack_ruleset_copy.test", "name", testCopyName),
+					resource.TestCheckResourceAttr("threatstack_ruleset_copy.test", "rule_ids.%", "1"),
+					testAccCheckThreatstackRulesetCopyHasRule("threatstack_ruleset_copy.test", "threatstack_host_rule.test"),
+				),
+			},
+			// Step 2: Rename copy
+			{
+				Config: testAccThreatstackRulesetCopy(testRulesetName, testCopyName2, testRuleName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("threatstack_ruleset_copy.test", "name", testCopyName2),
+					resource.TestCheckResourceAttr("threatstack_ruleset_copy.test", "rule_ids.%", "1"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckThreatstackRulesetCopyHasRule(copyName string, ruleName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		copyResource := s.RootModule().Resources[copyName]
+		ruleResource := s.RootModule().Resources[ruleName]
+
+		newID, ok := copyResource.Primary.Attributes[fmt.Sprintf("rule_ids.%s", ruleResource.Primary.ID)]
+		if !ok {
+			return fmt.Errorf("Rule ID %s not found in rule_ids for %s", ruleResource.Primary.ID, copyName)
+		}
+
+		_, err := testAccProvider.Meta().(*providerMeta).Client.Rules.Get(copyResource.Primary.ID, newID)
+		return err
+	}
+}
+
+func testAccThreatstackRulesetCopy(rsName, copyName, ruleName string) string {
+	return fmt.Sprintf(`
+resource "threatstack_ruleset" "test" {
+	name = "%s"
+
+	description = "Ruleset to be copied"
+}
+
+resource "threatstack_host_rule" "test" {
+	name = "%s"
+	title = "TEST"
+	description = "TEST"
+	ruleset = threatstack_ruleset.test.id
+	severity = 1
+	aggregate_fields = ["user"]
+	filter = "event_type = \"host\""
+	window = 86400
+	threshold = 1
+	suppressions = ["event_type != \"host\""]
+	enabled = true
+}
+
+resource "threatstack_ruleset_copy" "test" {
+	name = "%s"
+
+	description = "Copied ruleset"
+
+	source_ruleset_id = threatstack_host_rule.test.ruleset
+}
+`, rsName, ruleName, copyName)
+}
@@ -0,0 +1,362 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/jfcantu/threatstack-golang/threatstack"
+)
+
+// rulesetReplicaUnmanagedPrefix prefixes the rule_checksums keys of target
+// rules that don't correspond to any source rule, so that they show up in
+// the diff and are removed by the next apply.
+const rulesetReplicaUnmanagedPrefix = "target:"
+
+func resourceRulesetReplica() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceRulesetReplicaCreate,
+		Read:   resourceRulesetReplicaRead,
+		Update: resourceRulesetReplicaUpdate,
+		Delete: resourceRulesetReplicaDelete,
+		Importer: &schema.ResourceImporter{
+			State: importStateOrganization(schema.ImportStatePassthrough),
+		},
+		CustomizeDiff: customizeDiffRulesetReplica,
+
+		Schema: map[string]*schema.Schema{
+			"organization_id": organizationIDSchema(),
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"description": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"source_ruleset_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"source_organization_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"rule_ids": &schema.Schema{
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"rule_checksums": &schema.Schema{
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+// ruleChecksum returns a checksum of everything about a rule except its
+// identity, so that a rule and its replica have the same checksum.
+func ruleChecksum(rule threatstack.Rule) string {
+	attrs := liveRuleAttributes(rule)
+	attrs["type"] = fmt.Sprintf("%T", rule)
+
+	raw, _ := json.Marshal(attrs)
+	return fmt.Sprintf("%x", sha256.Sum256(raw))
+}
+
+func ruleName(rule threatstack.Rule) string {
+	name, _ := liveRuleAttributes(rule)["name"].(string)
+	return name
+}
+
+// rulesetReplicaSource retrieves the source ruleset and its rules.
+func rulesetReplicaSource(get func(string) interface{}, meta interface{}) (*rulesetRules, error) {
+	client, err := meta.(*providerMeta).OrgClient(get("source_organization_id").(string))
+	if err != nil {
+		return nil, err
+	}
+
+	return getRulesetRules(client, get("source_ruleset_id").(string))
+}
+
+// customizeDiffRulesetReplica plans rule_checksums from the source ruleset.
+// Read sets it from the target ruleset, so a change on either side shows up
+// in the diff.
+func customizeDiffRulesetReplica(d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("source_ruleset_id") || !d.NewValueKnown("source_organization_id") {
+		if err := d.SetNewComputed("rule_ids"); err != nil {
+			return err
+		}
+		return d.SetNewComputed("rule_checksums")
+	}
+
+	source, err := rulesetReplicaSource(d.Get, meta)
+	if err != nil {
+		return err
+	}
+
+	checksums := map[string]interface{}{}
+	for _, rule := range source.Rules {
+		checksums[rule.GetID()] = ruleChecksum(rule)
+	}
+
+	// rule_ids only changes if rules are added to or removed from the
+	// source.
+	current := d.Get("rule_ids").(map[string]interface{})
+	changed := len(current) != len(checksums)
+	for k := range checksums {
+		if _, ok := current[k]; !ok {
+			changed = true
+		}
+	}
+	if changed {
+		if err := d.SetNewComputed("rule_ids"); err != nil {
+			return err
+		}
+	}
+
+	return d.SetNew("rule_checksums", checksums)
+}
+
+func resourceRulesetReplicaCreate(resourceData *schema.ResourceData, meta interface{}) error {
+	client, err := resourceClient(resourceData, meta)
+	if err != nil {
+		return err
+	}
+
+	ruleset, err := client.Rulesets.Create(
+		&threatstack.Ruleset{
+			Name:        resourceData.Get("name").(string),
+			Description: resourceData.Get("description").(string),
+			RuleIDs:     []string{},
+		})
+	if err != nil {
+		return err
+	}
+
+	resourceData.SetId(ruleset.ID)
+
+	if err := syncRulesetReplica(resourceData, meta); err != nil {
+		return err
+	}
+
+	return resourceRulesetReplicaRead(resourceData, meta)
+}
+
+func resourceRulesetReplicaRead(resourceData *schema.ResourceData, meta interface{}) error {
+	client, err := resourceClient(resourceData, meta)
+	if err != nil {
+		return err
+	}
+
+	target, err := getRulesetRules(client, resourceData.Id())
+	if err != nil {
+		if strings.Contains(err.Error(), "404") {
+			resourceData.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	sourceIDs := map[string]string{}
+	for k, v := range resourceData.Get("rule_ids").(map[string]interface{}) {
+		sourceIDs[v.(string)] = k
+	}
+
+	// Replicas deleted from the target drop out of both maps, and rules
+	// added to the target by hand are checksummed under their own ID.
+	ruleIDs := map[string]string{}
+	checksums := map[string]string{}
+	for _, rule := range target.Rules {
+		if sourceID, ok := sourceIDs[rule.GetID()]; ok {
+			ruleIDs[sourceID] = rule.GetID()
+			checksums[sourceID] = ruleChecksum(rule)
+		} else {
+			checksums[rulesetReplicaUnmanagedPrefix+rule.GetID()] = ruleChecksum(rule)
+		}
+	}
+
+	resourceData.Set("name", target.Ruleset.Name)
+	resourceData.Set("description", target.Ruleset.Description)
+	resourceData.Set("rule_ids", ruleIDs)
+	resourceData.Set("rule_checksums", checksums)
+
+	return nil
+}
+
+func resourceRulesetReplicaUpdate(resourceData *schema.ResourceData, meta interface{}) error {
+	client, err := resourceClient(resourceData, meta)
+	if err != nil {
+		return err
+	}
+
+	if resourceData.HasChange("name") || resourceData.HasChange("description") {
+		current, err := client.Rulesets.Get(resourceData.Id())
+		if err != nil {
+			return err
+		}
+
+		_, err = client.Rulesets.Update(
+			&threatstack.Ruleset{
+				ID:          resourceData.Id(),
+				Name:        resourceData.Get("name").(string),
+				Description: resourceData.Get("description").(string),
+				RuleIDs:     current.RuleIDs,
+			})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := syncRulesetReplica(resourceData, meta); err != nil {
+		return err
+	}
+
+	return resourceRulesetReplicaRead(resourceData, meta)
+}
+
+func resourceRulesetReplicaDelete(resourceData *schema.ResourceData, meta interface{}) error {
+	client, err := resourceClient(resourceData, meta)
+	if err != nil {
+		return err
+	}
+
+	err = client.Rulesets.Delete(resourceData.Id())
+	if err != nil && !strings.Contains(err.Error(), "404") {
+		return err
+	}
+
+	return nil
+}
+
+// syncRulesetReplica makes the rules of the target ruleset match the source
+// ruleset: replicas of new source rules are created, replicas that differ
+// from their source rule are updated, and every other target rule is
+// deleted. Target rules that aren't replicas yet, e.g. after an import, are
+// adopted if they have the same name as a source rule.
+func syncRulesetReplica(resourceData *schema.ResourceData, meta interface{}) error {
+	client, err := resourceClient(resourceData, meta)
+	if err != nil {
+		return err
+	}
+
+	source, err := rulesetReplicaSource(resourceData.Get, meta)
+	if err != nil {
+		return err
+	}
+
+	target, err := getRulesetRules(client, resourceData.Id())
+	if err != nil {
+		return err
+	}
+
+	targetRules := map[string]threatstack.Rule{}
+	for _, rule := range target.Rules {
+		targetRules[rule.GetID()] = rule
+	}
+
+	ruleIDs := map[string]string{}
+	replicas := map[string]bool{}
+	// The planned rule_ids is unknown when the source rules change, so start
+	// from the previous mapping.
+	previous, _ := resourceData.GetChange("rule_ids")
+	for k, v := range previous.(map[string]interface{}) {
+		if _, ok := targetRules[v.(string)]; ok {
+			ruleIDs[k] = v.(string)
+			replicas[v.(string)] = true
+		}
+	}
+
+	unmanagedByName := map[string]string{}
+	for _, rule := range target.Rules {
+		if !replicas[rule.GetID()] {
+			unmanagedByName[ruleName(rule)] = rule.GetID()
+		}
+	}
+
+	// Keep rule_ids up to date as rules are created, so a partial failure
+	// doesn't leave orphaned replicas.
+	defer func() { resourceData.Set("rule_ids", ruleIDs) }()
+
+	for _, rule := range source.Rules {
+		sourceID := rule.GetID()
+
+		newRule, err := copyRule(rule)
+		if err != nil {
+			return err
+		}
+
+		targetID, ok := ruleIDs[sourceID]
+		if !ok {
+			if id, found := unmanagedByName[ruleName(rule)]; found {
+				log.Printf("[DEBUG] Adopting rule %s in ruleset %s as the replica of %s", id, target.Ruleset.ID, sourceID)
+				delete(unmanagedByName, ruleName(rule))
+				targetID, ok = id, true
+				ruleIDs[sourceID] = id
+				replicas[id] = true
+			}
+		}
+
+		if !ok {
+			log.Printf("[DEBUG] Replicating rule %s into ruleset %s", sourceID, target.Ruleset.ID)
+
+			created, err := client.Rules.Create(target.Ruleset.ID, newRule)
+			if err != nil {
+				return fmt.Errorf("Error replicating rule %s: %s", sourceID, err.Error())
+			}
+
+			ruleIDs[sourceID] = (*created).GetID()
+			replicas[(*created).GetID()] = true
+			continue
+		}
+
+		if ruleChecksum(targetRules[targetID]) == ruleChecksum(rule) {
+			continue
+		}
+
+		log.Printf("[DEBUG] Updating replica %s of rule %s in ruleset %s", targetID, sourceID, target.Ruleset.ID)
+
+		if _, err := client.Rules.Update(target.Ruleset.ID, targetID, newRule); err != nil {
+			return fmt.Errorf("Error updating replica %s of rule %s: %s", targetID, sourceID, err.Error())
+		}
+	}
+
+	sourceIDs := map[string]bool{}
+	for _, rule := range source.Rules {
+		sourceIDs[rule.GetID()] = true
+	}
+
+	for _, rule := range target.Rules {
+		id := rule.GetID()
+		if replicas[id] {
+			continue
+		}
+
+		log.Printf("[DEBUG] Deleting rule %s from ruleset %s, which isn't in the source ruleset", id, target.Ruleset.ID)
+
+		if err := client.Rules.Delete(target.Ruleset.ID, id); err != nil && !strings.Contains(err.Error(), "404") {
+			return fmt.Errorf("Error deleting rule %s: %s", id, err.Error())
+		}
+	}
+
+	// Replicas of rules that were removed from the source.
+	for sourceID, targetID := range ruleIDs {
+		if sourceIDs[sourceID] {
+			continue
+		}
+
+		log.Printf("[DEBUG] Deleting replica %s of removed rule %s from ruleset %s", targetID, sourceID, target.Ruleset.ID)
+
+		if err := client.Rules.Delete(target.Ruleset.ID, targetID); err != nil && !strings.Contains(err.Error(), "404") {
+			return fmt.Errorf("Error deleting replica %s of rule %s: %s", targetID, sourceID, err.Error())
+		}
+		delete(ruleIDs, sourceID)
+	}
+
+	return nil
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/jfcantu/threatstack-golang/threatstack"
+)
+
+func init() {
+	resource.AddTestSweepers("threatstack_ruleset_replica", &resource.Sweeper{
+		Name: "threatstack_ruleset_replica",
+		F:    sweepRulesets,
+	})
+}
+
+func TestAccThreatstackRulesetReplica_basic(test *testing.T) {
+	testRulesetName := fmt.Sprintf("tf%s", acctest.RandString(5))
+	testReplicaName := fmt.Sprintf("tf%s", acctest.RandString(5))
+	testRuleName := fmt.Sprintf("tf%s", acctest.RandString(5))
+
+	resource.Test(test, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(test) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckThreatstackRulesetDestroyed,
+		Steps: []resource.TestStep{
+			// Step 1: Replicate a ruleset with one rule
+			{
+				Config: testAccThreatstackRulesetReplica(testRulesetName, testReplicaName, testRuleName, 1),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckThreatstackRulesetExists("threatstack_ruleset_replica.test"),
+					resource.TestCheckResourceAttr("threatstack_ruleset_replica.test", "rule_ids.%", "1"),
+					resource.TestCheckResourceAttr("threatstack_ruleset_replica.test", "rule_checksums.%", "1"),
+				),
+			},
+			// Step 2: Change the source rule, which updates the replica
+			{
+				Config: testAccThreatstackRulesetReplica(testRulesetName, testReplicaName, testRuleName, 2),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckThreatstackRulesetReplicaSeverity("threatstack_ruleset_replica.test", "threatstack_host_rule.test", 2),
+				),
+			},
+			// Step 3: Delete the replica by hand, which shows up as drift
+			{
+				PreConfig: func() {
+					testAccDeleteRulesetReplicaRules(test, testReplicaName)
+				},
+				Config:             testAccThreatstackRulesetReplica(testRulesetName, testReplicaName, testRuleName, 2),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			// Step 4: Apply restores the replica
+			{
+				Config: testAccThreatstackRulesetReplica(testRulesetName, testReplicaName, testRuleName, 2),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("threatstack_ruleset_replica.test", "rule_ids.%", "1"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckThreatstackRulesetReplicaSeverity(replicaName string, ruleName string, severity int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		replicaResource := s.RootModule().Resources[replicaName]
+		ruleResource := s.RootModule().Resources[ruleName]
+
+		id, ok := replicaResource.Primary.Attributes[fmt.Sprintf("rule_ids.%s", ruleResource.Primary.ID)]
+		if !ok {
+			return fmt.Errorf("Rule ID %s not found in rule_ids for %s", ruleResource.Primary.ID, replicaName)
+		}
+
+		rule, err := testAccProvider.Meta().(*providerMeta).Client.Rules.Get(replicaResource.Primary.ID, id)
+		if err != nil {
+			return err
+		}
+
+		if got := (*rule).(*threatstack.HostRule).Severity; got != severity {
+			return fmt.Errorf("Expected replica severity %d, got %d", severity, got)
+		}
+		return nil
+	}
+}
+
+func testAccDeleteRulesetReplicaRules(test *testing.T, name string) {
+	client := testAccProvider.Meta().(*providerMeta).Client
+
+	rulesets, err := client.Rulesets.List()
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	for _, v := range rulesets {
+		if v.Name != name {
+			continue
+		}
+
+		ruleset, err := client.Rulesets.Get(v.ID)
+		if err != nil {
+			test.Fatal(err)
+		}
+		for _, id := range ruleset.RuleIDs {
+			if err := client.Rules.Delete(ruleset.ID, id); err != nil {
+				test.Fatal(err)
+			}
+		}
+	}
+}
+
+func testAccThreatstackRulesetReplica(rsName, replicaName, ruleName string, severity int) string {
+	return fmt.Sprintf(`
+resource "threatstack_ruleset" "test" {
+	name = "%s"
+
+	description = "Ruleset to be replicated"
+}
+
+resource "threatstack_host_rule" "test" {
+	name = "%s"
+	title = "TEST"
+	description = "TEST"
+	ruleset = threatstack_ruleset.test.id
+	severity = %d
+	aggregate_fields = ["user"]
+	filter = "event_type = \"host\""
+	window = 86400
+	threshold = 1
+	enabled = true
+}
+
+resource "threatstack_ruleset_replica" "test" {
+	name = "%s"
+
+	description = "Replicated ruleset"
+
+	source_ruleset_id = threatstack_host_rule.test.ruleset
+}
+`, rsName, ruleName, severity, replicaName)
+}
+
+func TestRuleChecksum(test *testing.T) {
+	rule := &threatstack.HostRule{
+		ID:        "r1",
+		RulesetID: "rs1",
+		Name:      "New user",
+		Severity:  1,
+		Filter:    "event_type = \"host\"",
+		Window:    3600,
+		Threshold: 1,
+		Tags:      threatstack.NewTagSet(),
+	}
+
+	replica, err := copyRule(rule)
+	if err != nil {
+		test.Fatal(err)
+	}
+	if ruleChecksum(rule) != ruleChecksum(replica) {
+		test.Error("Expected a rule and its copy to have the same checksum")
+	}
+
+	replica.(*threatstack.HostRule).Severity = 2
+	if ruleChecksum(rule) == ruleChecksum(replica) {
+		test.Error("Expected a changed rule to have a different checksum")
+	}
+
+	file := &threatstack.FileRule{Name: "New user", Severity: 1, Filter: "event_type = \"host\"", Window: 3600, Threshold: 1}
+	if ruleChecksum(rule) == ruleChecksum(file) {
+		test.Error("Expected host and file rules to have different checksums")
+	}
+}
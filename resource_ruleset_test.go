@@ -6,9 +6,9 @@ import (
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
 	"github.com/hashicorp/terraform/helper/acctest"
-	"github.com/jfcantu/threatstack-golang/threatstack"
 )
 
 func init() {
@@ -61,7 +61,7 @@ func TestAccThreatstackRuleset_basic(test *testing.T) {
 
 func testAccCheckThreatstackRulesetExists(name string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		cli := testAccProvider.Meta().(*threatstack.Client)
+		cli := testAccProvider.Meta().(*providerMeta).Client
 
 		res, ok := s.RootModule().Resources[name]
 		if !ok {
@@ -85,7 +85,7 @@ func testAccCheckThreatstackRulesetHasRule(rulesetName string, ruleName string)
 		rsResource := s.RootModule().Resources[rulesetName]
 		ruleResource := s.RootModule().Resources[ruleName]
 
-		ruleset, err := testAccProvider.Meta().(*threatstack.Client).Rulesets.Get(rsResource.Primary.ID)
+		ruleset, err := testAccProvider.Meta().(*providerMeta).Client.Rulesets.Get(rsResource.Primary.ID)
 		if err != nil {
 			return err
 		}
@@ -101,7 +101,7 @@ func testAccCheckThreatstackRulesetHasRule(rulesetName string, ruleName string)
 }
 
 func testAccCheckThreatstackRulesetDestroyed(s *terraform.State) error {
-	cli := testAccProvider.Meta().(*threatstack.Client)
+	cli := testAccProvider.Meta().(*providerMeta).Client
 
 	for _, res := range s.RootModule().Resources {
 		if res.Type != "threatstack_ruleset" {
@@ -158,3 +158,19 @@ resource "threatstack_host_rule" "test" {
 	}
 `, rsName, rsDesc, ruleName)
 }
+
+func TestResourceRulesetReadNotFound(test *testing.T) {
+	meta := &providerMeta{Client: testAPIClient(test, map[string]string{
+		"/v2/rulesets/gone": testAPINotFound,
+	})}
+
+	resourceData := schema.TestResourceDataRaw(test, resourceRuleset().Schema, map[string]interface{}{})
+	resourceData.SetId("gone")
+
+	if err := resourceRulesetRead(resourceData, meta); err != nil {
+		test.Fatal(err)
+	}
+	if resourceData.Id() != "" {
+		test.Errorf("Expected a deleted ruleset to be removed from state, got ID %q", resourceData.Id())
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+func resourceVulnerabilitySuppression() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceVulnerabilitySuppressionCreate,
+		Read:   resourceVulnerabilitySuppressionRead,
+		Update: resourceVulnerabilitySuppressionUpdate,
+		Delete: resourceVulnerabilitySuppressionDelete,
+		Importer: &schema.ResourceImporter{
+			State: importStateOrganization(schema.ImportStatePassthrough),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"organization_id": organizationIDSchema(),
+			"cve_id": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(cvePattern, "must be a CVE ID, e.g. CVE-2020-1234"),
+			},
+			"package": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"agent_ids": &schema.Schema{
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"justification": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+			},
+			"expires_at": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+			},
+			"created_at": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"created_by": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// expandVulnerabilitySuppression builds a suppression from configuration. An
+// expiry in the past is an error, since the API would drop the suppression
+// right away and every plan would recreate it.
+func expandVulnerabilitySuppression(resourceData *schema.ResourceData) (*vulnerabilitySuppression, error) {
+	suppression := &vulnerabilitySuppression{
+		CVENumber: resourceData.Get("cve_id").(string),
+		Package:   resourceData.Get("package").(string),
+		Reason:    resourceData.Get("justification").(string),
+		ExpiresAt: resourceData.Get("expires_at").(string),
+	}
+
+	for _, v := range resourceData.Get("agent_ids").(*schema.Set).List() {
+		suppression.AgentIDs = append(suppression.AgentIDs, v.(string))
+	}
+	suppression.AgentIDs = sortedStrings(suppression.AgentIDs)
+
+	if suppression.ExpiresAt != "" {
+		expires, err := time.Parse(time.RFC3339, suppression.ExpiresAt)
+		if err != nil {
+			return nil, err
+		}
+		if !expires.After(time.Now()) {
+			return nil, fmt.Errorf("expires_at %s is in the past", suppression.ExpiresAt)
+		}
+	}
+
+	return suppression, nil
+}
+
+func resourceVulnerabilitySuppressionCreate(resourceData *schema.ResourceData, meta interface{}) error {
+	client, err := resourceClient(resourceData, meta)
+	if err != nil {
+		return err
+	}
+
+	suppression, err := expandVulnerabilitySuppression(resourceData)
+	if err != nil {
+		return err
+	}
+
+	raw, err := client.CreateObject("vulnerabilities/suppressions", nil, suppression)
+	if err != nil {
+		return err
+	}
+
+	resp := new(vulnerabilitySuppression)
+	if err := json.Unmarshal(raw, resp); err != nil {
+		return err
+	}
+
+	resourceData.SetId(resp.ID)
+	return resourceVulnerabilitySuppressionRead(resourceData, meta)
+}
+
+func resourceVulnerabilitySuppressionRead(resourceData *schema.ResourceData, meta interface{}) error {
+	client, err := resourceClient(resourceData, meta)
+	if err != nil {
+		return err
+	}
+
+	raw, err := client.GetObject(fmt.Sprintf("vulnerabilities/suppressions/%s", resourceData.Id()), nil)
+	if err != nil {
+		// Expired suppressions are removed by the API, and are recreated on
+		// the next apply if they're still configured.
+		if strings.Contains(err.Error(), "404") {
+			resourceData.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	resp := new(vulnerabilitySuppression)
+	if err := json.Unmarshal(raw, resp); err != nil {
+		return err
+	}
+
+	resourceData.Set("cve_id", resp.CVENumber)
+	resourceData.Set("package", resp.Package)
+	resourceData.Set("agent_ids", resp.AgentIDs)
+	resourceData.Set("justification", resp.Reason)
+	resourceData.Set("expires_at", resp.ExpiresAt)
+	resourceData.Set("created_at", resp.CreatedAt)
+	resourceData.Set("created_by", resp.CreatedBy)
+
+	return nil
+}
+
+func resourceVulnerabilitySuppressionUpdate(resourceData *schema.ResourceData, meta interface{}) error {
+	client, err := resourceClient(resourceData, meta)
+	if err != nil {
+		return err
+	}
+
+	suppression, err := expandVulnerabilitySuppression(resourceData)
+	if err != nil {
+		return err
+	}
+
+	if _, err := client.UpdateObject(fmt.Sprintf("vulnerabilities/suppressions/%s", resourceData.Id()), nil, suppression); err != nil {
+		return err
+	}
+
+	return resourceVulnerabilitySuppressionRead(resourceData, meta)
+}
+
+func resourceVulnerabilitySuppressionDelete(resourceData *schema.ResourceData, meta interface{}) error {
+	client, err := resourceClient(resourceData, meta)
+	if err != nil {
+		return err
+	}
+
+	err = client.DeleteObject(fmt.Sprintf("vulnerabilities/suppressions/%s", resourceData.Id()), nil)
+	if err != nil && !strings.Contains(err.Error(), "404") {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestAccThreatstackVulnerabilitySuppression_basic(test *testing.T) {
+	resource.Test(test, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(test) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckThreatstackVulnerabilitySuppressionDestroyed,
+		Steps: []resource.TestStep{
+			// Step 1: Suppress a CVE
+			{
+				Config: testAccThreatstackVulnerabilitySuppression("Accepted risk"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("threatstack_vulnerability_suppression.test", "cve_id", "CVE-2014-0160"),
+					resource.TestCheckResourceAttr("threatstack_vulnerability_suppression.test", "justification", "Accepted risk"),
+					resource.TestCheckResourceAttrSet("threatstack_vulnerability_suppression.test", "created_at"),
+				),
+			},
+			// Step 2: Change the justification
+			{
+				Config: testAccThreatstackVulnerabilitySuppression("Still accepted risk"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("threatstack_vulnerability_suppression.test", "justification", "Still accepted risk"),
+				),
+			},
+			// Step 3: Import
+			{
+				ResourceName:      "threatstack_vulnerability_suppression.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckThreatstackVulnerabilitySuppressionDestroyed(s *terraform.State) error {
+	client := testAccProvider.Meta().(*providerMeta).Client
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "threatstack_vulnerability_suppression" {
+			continue
+		}
+
+		_, err := client.GetObject(fmt.Sprintf("vulnerabilities/suppressions/%s", rs.Primary.ID), nil)
+		if err == nil {
+			return fmt.Errorf("Vulnerability suppression %s still exists", rs.Primary.ID)
+		}
+		if !strings.Contains(err.Error(), "404") {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func testAccThreatstackVulnerabilitySuppression(justification string) string {
+	return fmt.Sprintf(`
+resource "threatstack_vulnerability_suppression" "test" {
+	cve_id = "CVE-2014-0160"
+	package = "openssl"
+	justification = "%s"
+	expires_at = "2099-01-01T00:00:00Z"
+}
+`, justification)
+}
+
+func TestExpandVulnerabilitySuppressionExpired(test *testing.T) {
+	resourceData := schema.TestResourceDataRaw(test, resourceVulnerabilitySuppression().Schema, map[string]interface{}{
+		"cve_id":        "CVE-2014-0160",
+		"justification": "Accepted risk",
+		"expires_at":    "2000-01-01T00:00:00Z",
+	})
+
+	if _, err := expandVulnerabilitySuppression(resourceData); err == nil || !strings.Contains(err.Error(), "in the past") {
+		test.Errorf("Expected an error for an expiry in the past, got %v", err)
+	}
+}
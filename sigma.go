@@ -0,0 +1,438 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// sigmaRule is the result of translating a Sigma rule into the fields of a
+// Threat Stack host rule.
+type sigmaRule struct {
+	Title       string
+	Description string
+	Severity    int
+	Filter      string
+}
+
+type sigmaDocument struct {
+	Title       string        `yaml:"title"`
+	Description string        `yaml:"description"`
+	Level       string        `yaml:"level"`
+	Logsource   sigmaLogsrc   `yaml:"logsource"`
+	Detection   yaml.MapSlice `yaml:"detection"`
+}
+
+type sigmaLogsrc struct {
+	Product  string `yaml:"product"`
+	Category string `yaml:"category"`
+	Service  string `yaml:"service"`
+}
+
+// sigmaSeverities maps Sigma levels to Threat Stack severities, where 1 is
+// the most severe.
+var sigmaSeverities = map[string]int{
+	"critical":      1,
+	"high":          1,
+	"medium":        2,
+	"low":           3,
+	"informational": 3,
+}
+
+// sigmaCategoryFilters maps Linux Sigma log sources to the Threat Stack host
+// events that contain them.
+var sigmaCategoryFilters = map[string]string{
+	"process_creation":   `event_type = "audit" and syscall = "execve"`,
+	"network_connection": `event_type = "audit" and syscall = "connect"`,
+	"file_event":         `event_type = "audit" and syscall = "open"`,
+}
+
+// sigmaFields maps Sigma field names to Threat Stack host event fields.
+var sigmaFields = map[string]string{
+	"Image":            "exe",
+	"exe":              "exe",
+	"comm":             "command",
+	"CommandLine":      "arguments",
+	"User":             "user",
+	"CurrentDirectory": "cwd",
+	"cwd":              "cwd",
+	"ProcessId":        "pid",
+	"pid":              "pid",
+	"ParentProcessId":  "ppid",
+	"ppid":             "ppid",
+	"TargetFilename":   "filename",
+	"name":             "filename",
+	"SourceIp":         "src_ip",
+	"SourcePort":       "src_port",
+	"DestinationIp":    "dst_ip",
+	"DestinationPort":  "dst_port",
+	"syscall":          "syscall",
+	"uid":              "uid",
+	"gid":              "gid",
+	"tty":              "tty",
+	"ses":              "session",
+}
+
+// compileSigmaRule translates a Sigma rule document into a Threat Stack host
+// rule. Only Linux log sources, field/value selections and the contains,
+// startswith, endswith and all modifiers are supported; anything else is
+// reported as an error rather than being silently dropped.
+func compileSigmaRule(content string) (*sigmaRule, error) {
+	doc := new(sigmaDocument)
+	if err := yaml.Unmarshal([]byte(content), doc); err != nil {
+		return nil, fmt.Errorf("Error parsing Sigma rule: %s", err.Error())
+	}
+
+	if doc.Title == "" {
+		return nil, fmt.Errorf("Sigma rule has no title")
+	}
+
+	severity := 2
+	if doc.Level != "" {
+		var ok bool
+		if severity, ok = sigmaSeverities[doc.Level]; !ok {
+			return nil, fmt.Errorf("Unsupported Sigma level %q", doc.Level)
+		}
+	}
+
+	logsource, err := compileSigmaLogsource(doc.Logsource)
+	if err != nil {
+		return nil, err
+	}
+
+	selections := map[string]filterExpr{}
+	var selectionNames []string
+	var conditions []string
+
+	for _, item := range doc.Detection {
+		name := fmt.Sprint(item.Key)
+
+		switch name {
+		case "condition":
+			switch v := item.Value.(type) {
+			case string:
+				conditions = append(conditions, v)
+			case []interface{}:
+				for _, c := range v {
+					conditions = append(conditions, fmt.Sprint(c))
+				}
+			default:
+				return nil, fmt.Errorf("Unsupported Sigma condition %v", item.Value)
+			}
+		case "timeframe":
+			return nil, fmt.Errorf("Sigma timeframe is not supported, set the rule window instead")
+		default:
+			expr, err := compileSigmaSelection(name, item.Value)
+			if err != nil {
+				return nil, err
+			}
+			selections[name] = expr
+			selectionNames = append(selectionNames, name)
+		}
+	}
+
+	if len(conditions) == 0 {
+		return nil, fmt.Errorf("Sigma rule has no detection condition")
+	}
+
+	var compiled []filterExpr
+	for _, v := range conditions {
+		expr, err := compileSigmaCondition(v, selections, selectionNames)
+		if err != nil {
+			return nil, err
+		}
+		compiled = append(compiled, expr)
+	}
+
+	filter := joinFilterExprs([]filterExpr{
+		{logsource, filterPrecAnd},
+		joinFilterExprs(compiled, "or", filterPrecOr),
+	}, "and", filterPrecAnd)
+
+	return &sigmaRule{
+		Title:       doc.Title,
+		Description: strings.TrimSpace(doc.Description),
+		Severity:    severity,
+		Filter:      filter.filter,
+	}, nil
+}
+
+func compileSigmaLogsource(logsource sigmaLogsrc) (string, error) {
+	if logsource.Product != "" && logsource.Product != "linux" {
+		return "", fmt.Errorf("Unsupported Sigma logsource product %q, only linux is supported", logsource.Product)
+	}
+
+	if logsource.Category != "" {
+		filter, ok := sigmaCategoryFilters[logsource.Category]
+		if !ok {
+			return "", fmt.Errorf("Unsupported Sigma logsource category %q", logsource.Category)
+		}
+		return filter, nil
+	}
+
+	switch logsource.Service {
+	case "auditd", "":
+		return `event_type = "audit"`, nil
+	default:
+		return "", fmt.Errorf("Unsupported Sigma logsource service %q", logsource.Service)
+	}
+}
+
+// compileSigmaSelection compiles a detection selection: a map is a
+// conjunction of field matches, and a list of maps is a disjunction of them.
+func compileSigmaSelection(name string, selection interface{}) (filterExpr, error) {
+	switch v := selection.(type) {
+	case yaml.MapSlice:
+		var exprs []filterExpr
+		for _, field := range v {
+			expr, err := compileSigmaField(fmt.Sprint(field.Key), field.Value)
+			if err != nil {
+				return filterExpr{}, fmt.Errorf("Error in Sigma selection %s: %s", name, err.Error())
+			}
+			exprs = append(exprs, expr)
+		}
+		if len(exprs) == 0 {
+			return filterExpr{}, fmt.Errorf("Sigma selection %s is empty", name)
+		}
+		return joinFilterExprs(exprs, "and", filterPrecAnd), nil
+	case []interface{}:
+		var exprs []filterExpr
+		for _, item := range v {
+			if _, ok := item.(yaml.MapSlice); !ok {
+				return filterExpr{}, fmt.Errorf("Sigma keyword selection %s is not supported, use field names", name)
+			}
+			expr, err := compileSigmaSelection(name, item)
+			if err != nil {
+				return filterExpr{}, err
+			}
+			exprs = append(exprs, expr)
+		}
+		if len(exprs) == 0 {
+			return filterExpr{}, fmt.Errorf("Sigma selection %s is empty", name)
+		}
+		return joinFilterExprs(exprs, "or", filterPrecOr), nil
+	default:
+		return filterExpr{}, fmt.Errorf("Unsupported Sigma selection %s", name)
+	}
+}
+
+func compileSigmaField(key string, value interface{}) (filterExpr, error) {
+	parts := strings.Split(key, "|")
+
+	field, ok := sigmaFields[parts[0]]
+	if !ok {
+		return filterExpr{}, fmt.Errorf("Sigma field %q has no Threat Stack equivalent", parts[0])
+	}
+
+	var match string
+	all := false
+	for _, modifier := range parts[1:] {
+		switch modifier {
+		case "contains", "startswith", "endswith":
+			if match != "" {
+				return filterExpr{}, fmt.Errorf("Sigma modifiers %s and %s can't be combined", match, modifier)
+			}
+			match = modifier
+		case "all":
+			all = true
+		default:
+			return filterExpr{}, fmt.Errorf("Sigma modifier %q is not supported", modifier)
+		}
+	}
+
+	var values []interface{}
+	if list, ok := value.([]interface{}); ok {
+		values = list
+	} else {
+		values = []interface{}{value}
+	}
+
+	var exprs []filterExpr
+	for _, v := range values {
+		expr, err := compileSigmaValue(field, match, v)
+		if err != nil {
+			return filterExpr{}, err
+		}
+		exprs = append(exprs, expr)
+	}
+
+	if all {
+		return joinFilterExprs(exprs, "and", filterPrecAnd), nil
+	}
+	return joinFilterExprs(exprs, "or", filterPrecOr), nil
+}
+
+func compileSigmaValue(field, match string, value interface{}) (filterExpr, error) {
+	switch v := value.(type) {
+	case int:
+		if match != "" {
+			return filterExpr{}, fmt.Errorf("Sigma modifier %s can't be used on numbers", match)
+		}
+		return filterExpr{fmt.Sprintf("%s = %d", field, v), filterPrecAtom}, nil
+	case string:
+		// Sigma wildcards become LIKE patterns; literal LIKE wildcards in
+		// the value can't be expressed.
+		if strings.ContainsAny(v, "%_") && (match != "" || strings.ContainsAny(v, "*?")) {
+			return filterExpr{}, fmt.Errorf("Sigma value %q contains %% or _, which can't be used in a pattern", v)
+		}
+
+		pattern := strings.NewReplacer("*", "%", "?", "_").Replace(v)
+		switch match {
+		case "contains":
+			pattern = "%" + pattern + "%"
+		case "startswith":
+			pattern = pattern + "%"
+		case "endswith":
+			pattern = "%" + pattern
+		}
+
+		if pattern == v {
+			return filterExpr{fmt.Sprintf("%s = %s", field, strconv.Quote(v)), filterPrecAtom}, nil
+		}
+		return filterExpr{fmt.Sprintf("%s LIKE %s", field, strconv.Quote(pattern)), filterPrecAtom}, nil
+	case nil:
+		return filterExpr{}, fmt.Errorf("Sigma null values are not supported")
+	default:
+		return filterExpr{}, fmt.Errorf("Unsupported Sigma value %v", value)
+	}
+}
+
+var sigmaConditionToken = regexp.MustCompile(`\(|\)|\||[^\s()|]+`)
+
+// sigmaConditionParser is a recursive descent parser for Sigma conditions:
+//
+//	expr    = and { "or" and }
+//	and     = not { "and" not }
+//	not     = "not" not | primary
+//	primary = "(" expr ")" | ("1" | "all") "of" (pattern | "them") | name
+type sigmaConditionParser struct {
+	tokens     []string
+	pos        int
+	selections map[string]filterExpr
+	names      []string
+}
+
+func compileSigmaCondition(condition string, selections map[string]filterExpr, names []string) (filterExpr, error) {
+	parser := &sigmaConditionParser{
+		tokens:     sigmaConditionToken.FindAllString(condition, -1),
+		selections: selections,
+		names:      names,
+	}
+
+	expr, err := parser.or()
+	if err != nil {
+		return filterExpr{}, fmt.Errorf("Error in Sigma condition %q: %s", condition, err.Error())
+	}
+	if parser.pos < len(parser.tokens) {
+		token := parser.tokens[parser.pos]
+		if token == "|" {
+			return filterExpr{}, fmt.Errorf("Sigma aggregations in condition %q are not supported", condition)
+		}
+		return filterExpr{}, fmt.Errorf("Unexpected %q in Sigma condition %q", token, condition)
+	}
+
+	return expr, nil
+}
+
+func (p *sigmaConditionParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *sigmaConditionParser) next() string {
+	token := p.peek()
+	p.pos++
+	return token
+}
+
+func (p *sigmaConditionParser) or() (filterExpr, error) {
+	return p.binary("or", filterPrecOr, p.and)
+}
+
+func (p *sigmaConditionParser) and() (filterExpr, error) {
+	return p.binary("and", filterPrecAnd, p.not)
+}
+
+func (p *sigmaConditionParser) binary(op string, prec int, operand func() (filterExpr, error)) (filterExpr, error) {
+	expr, err := operand()
+	if err != nil {
+		return filterExpr{}, err
+	}
+
+	exprs := []filterExpr{expr}
+	for p.peek() == op {
+		p.next()
+		expr, err := operand()
+		if err != nil {
+			return filterExpr{}, err
+		}
+		exprs = append(exprs, expr)
+	}
+
+	return joinFilterExprs(exprs, op, prec), nil
+}
+
+func (p *sigmaConditionParser) not() (filterExpr, error) {
+	if p.peek() != "not" {
+		return p.primary()
+	}
+	p.next()
+
+	expr, err := p.not()
+	if err != nil {
+		return filterExpr{}, err
+	}
+	return notFilterExpr(expr), nil
+}
+
+func (p *sigmaConditionParser) primary() (filterExpr, error) {
+	token := p.next()
+
+	switch token {
+	case "":
+		return filterExpr{}, fmt.Errorf("unexpected end of condition")
+	case "(":
+		expr, err := p.or()
+		if err != nil {
+			return filterExpr{}, err
+		}
+		if p.next() != ")" {
+			return filterExpr{}, fmt.Errorf("missing )")
+		}
+		return expr, nil
+	case "1", "all":
+		if p.next() != "of" {
+			return filterExpr{}, fmt.Errorf("expected \"of\" after %q", token)
+		}
+		pattern := p.next()
+
+		var exprs []filterExpr
+		for _, name := range p.names {
+			if matched, _ := path.Match(pattern, name); matched || pattern == "them" {
+				exprs = append(exprs, p.selections[name])
+			}
+		}
+		if len(exprs) == 0 {
+			return filterExpr{}, fmt.Errorf("no selections match %q", pattern)
+		}
+
+		if token == "all" {
+			return joinFilterExprs(exprs, "and", filterPrecAnd), nil
+		}
+		return joinFilterExprs(exprs, "or", filterPrecOr), nil
+	case ")", "|", "and", "or", "not", "of":
+		return filterExpr{}, fmt.Errorf("unexpected %q", token)
+	default:
+		expr, ok := p.selections[token]
+		if !ok {
+			return filterExpr{}, fmt.Errorf("unknown selection %q", token)
+		}
+		return expr, nil
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompileSigmaRule(test *testing.T) {
+	rule, err := compileSigmaRule(`
+title: Netcat Reverse Shell
+description: Detects netcat being used to spawn a shell.
+level: high
+logsource:
+  product: linux
+  category: process_creation
+detection:
+  selection:
+    Image|endswith:
+      - /nc
+      - /ncat
+    CommandLine|contains|all:
+      - ' -e '
+      - /bin/sh
+  filter:
+    User: root
+  condition: selection and not filter
+`)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	if rule.Title != "Netcat Reverse Shell" {
+		test.Errorf("Title: %q", rule.Title)
+	}
+	if rule.Description != "Detects netcat being used to spawn a shell." {
+		test.Errorf("Description: %q", rule.Description)
+	}
+	if rule.Severity != 1 {
+		test.Errorf("Severity: %d", rule.Severity)
+	}
+
+	expected := `event_type = "audit" and syscall = "execve" and ` +
+		`(exe LIKE "%/nc" or exe LIKE "%/ncat") and arguments LIKE "% -e %" and arguments LIKE "%/bin/sh%" and ` +
+		`not (user = "root")`
+	if rule.Filter != expected {
+		test.Errorf("Filter:\n%s\n\nExpected:\n%s", rule.Filter, expected)
+	}
+}
+
+func TestCompileSigmaRuleConditions(test *testing.T) {
+	for _, v := range []struct {
+		condition string
+		expected  string
+	}{
+		{"1 of sel*", `event_type = "audit" and (exe = "/bin/a" or dst_port = 4444)`},
+		{"all of them", `event_type = "audit" and exe = "/bin/a" and dst_port = 4444 and exe LIKE "/tmp/%"`},
+		{"(sel1 or sel2) and not other", `event_type = "audit" and (exe = "/bin/a" or dst_port = 4444) and not (exe LIKE "/tmp/%")`},
+	} {
+		rule, err := compileSigmaRule(`
+title: test
+logsource:
+  service: auditd
+detection:
+  sel1:
+    exe: /bin/a
+  sel2:
+    DestinationPort: 4444
+  other:
+    exe: /tmp/*
+  condition: ` + v.condition)
+		if err != nil {
+			test.Errorf("%s: %s", v.condition, err)
+			continue
+		}
+
+		if rule.Filter != v.expected {
+			test.Errorf("%s:\n%s\n\nExpected:\n%s", v.condition, rule.Filter, v.expected)
+		}
+	}
+}
+
+func TestCompileSigmaRuleUnsupported(test *testing.T) {
+	for _, v := range []struct {
+		detection string
+		expected  string
+	}{
+		{"sel:\n    CommandLine|re: 'a.*b'\n  condition: sel", `modifier "re" is not supported`},
+		{"sel:\n    CommandLine|base64offset|contains: abc\n  condition: sel", `modifier "base64offset" is not supported`},
+		{"sel:\n    ParentImage: /bin/sh\n  condition: sel", `"ParentImage" has no Threat Stack equivalent`},
+		{"sel:\n    - keyword\n  condition: sel", "keyword selection"},
+		{"sel:\n    exe: /bin/a\n  condition: sel | count() > 5", "aggregations"},
+		{"sel:\n    exe: /bin/a\n  condition: missing", `unknown selection "missing"`},
+	} {
+		_, err := compileSigmaRule("title: test\nlogsource:\n  product: linux\ndetection:\n  " + v.detection)
+		if err == nil || !strings.Contains(err.Error(), v.expected) {
+			test.Errorf("Expected error containing %q, got %v", v.expected, err)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jfcantu/threatstack-golang/threatstack"
+)
+
+// snapshotVersion is bumped whenever the snapshot format changes in a way
+// older versions of the provider can't read.
+const snapshotVersion = 1
+
+// snapshot is a point-in-time copy of every ruleset and rule in an
+// organization.
+type snapshot struct {
+	Version        int                `json:"version"`
+	CreatedAt      string             `json:"created_at"`
+	OrganizationID string             `json:"organization_id"`
+	Rulesets       []*snapshotRuleset `json:"rulesets"`
+}
+
+type snapshotRuleset struct {
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Rules       []*snapshotRule `json:"rules"`
+}
+
+// snapshotRule holds exactly one of Host or File. Tags are stored separately
+// because the rule types don't serialize them.
+type snapshotRule struct {
+	Host *threatstack.HostRule `json:"host,omitempty"`
+	File *threatstack.FileRule `json:"file,omitempty"`
+	Tags *threatstack.TagSet   `json:"tags"`
+}
+
+func (r *snapshotRule) rule() (threatstack.Rule, error) {
+	switch {
+	case r.Host != nil:
+		r.Host.Tags = r.Tags
+		return r.Host, nil
+	case r.File != nil:
+		r.File.Tags = r.Tags
+		return r.File, nil
+	default:
+		return nil, fmt.Errorf("Snapshot rule has no definition")
+	}
+}
+
+// snapshotMapping maps the IDs in a snapshot to the IDs of the objects
+// created when restoring it.
+type snapshotMapping struct {
+	Rulesets map[string]string `json:"rulesets"`
+	Rules    map[string]string `json:"rules"`
+}
+
+func newSnapshot(orgID string, rulesets []*rulesetRules) (*snapshot, error) {
+	snap := &snapshot{
+		Version:        snapshotVersion,
+		CreatedAt:      time.Now().UTC().Format(time.RFC3339),
+		OrganizationID: orgID,
+		Rulesets:       []*snapshotRuleset{},
+	}
+
+	for _, rs := range rulesets {
+		entry := &snapshotRuleset{
+			ID:          rs.Ruleset.ID,
+			Name:        rs.Ruleset.Name,
+			Description: rs.Ruleset.Description,
+			Rules:       []*snapshotRule{},
+		}
+
+		for _, rule := range rs.Rules {
+			switch r := rule.(type) {
+			case *threatstack.HostRule:
+				entry.Rules = append(entry.Rules, &snapshotRule{Host: r, Tags: r.Tags})
+			case *threatstack.FileRule:
+				entry.Rules = append(entry.Rules, &snapshotRule{File: r, Tags: r.Tags})
+			default:
+				return nil, fmt.Errorf("Unsupported rule type %T", rule)
+			}
+		}
+
+		snap.Rulesets = append(snap.Rulesets, entry)
+	}
+
+	return snap, nil
+}
+
+func readSnapshot(r io.Reader) (*snapshot, error) {
+	snap := new(snapshot)
+	if err := json.NewDecoder(r).Decode(snap); err != nil {
+		return nil, fmt.Errorf("Error reading snapshot: %s", err.Error())
+	}
+
+	if snap.Version != snapshotVersion {
+		return nil, fmt.Errorf("Unsupported snapshot version %d (expected %d)", snap.Version, snapshotVersion)
+	}
+
+	return snap, nil
+}
+
+// restoreSnapshot recreates every ruleset and rule in the snapshot as new
+// objects. On error, the mapping of everything restored so far is returned
+// along with the error.
+func restoreSnapshot(client *threatstack.Client, snap *snapshot) (*snapshotMapping, error) {
+	mapping := &snapshotMapping{
+		Rulesets: map[string]string{},
+		Rules:    map[string]string{},
+	}
+
+	for _, rs := range snap.Rulesets {
+		ruleset, err := client.Rulesets.Create(
+			&threatstack.Ruleset{
+				Name:        rs.Name,
+				Description: rs.Description,
+				RuleIDs:     []string{},
+			})
+		if err != nil {
+			return mapping, fmt.Errorf("Error creating ruleset %s: %s", rs.Name, err.Error())
+		}
+
+		mapping.Rulesets[rs.ID] = ruleset.ID
+
+		for _, v := range rs.Rules {
+			rule, err := v.rule()
+			if err != nil {
+				return mapping, err
+			}
+
+			newRule, err := copyRule(rule)
+			if err != nil {
+				return mapping, err
+			}
+
+			created, err := client.Rules.Create(ruleset.ID, newRule)
+			if err != nil {
+				return mapping, fmt.Errorf("Error creating rule %s: %s", rule.GetID(), err.Error())
+			}
+
+			mapping.Rules[rule.GetID()] = (*created).GetID()
+		}
+	}
+
+	return mapping, nil
+}
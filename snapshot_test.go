@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jfcantu/threatstack-golang/threatstack"
+)
+
+func TestSnapshotRoundTrip(test *testing.T) {
+	hostRule := &threatstack.HostRule{
+		ID:              "r1",
+		Type:            "Host",
+		Name:            "host",
+		Title:           "host",
+		Severity:        1,
+		AggregateFields: []string{"user"},
+		Filter:          `event_type = "host"`,
+		Window:          3600,
+		Threshold:       1,
+		Enabled:         true,
+		Tags: &threatstack.TagSet{
+			Include: []*threatstack.Tag{{Source: "ec2", Key: "env", Value: "prod"}},
+			Exclude: []*threatstack.Tag{},
+		},
+	}
+	fileRule := &threatstack.FileRule{
+		ID:            "r2",
+		Type:          "File",
+		Name:          "file",
+		Title:         "file",
+		Severity:      2,
+		Window:        86400,
+		Threshold:     1,
+		Paths:         []*threatstack.FilePath{{Path: "/etc", Recursive: true}},
+		IgnoreFiles:   []string{"*.swp"},
+		MonitorEvents: []string{"open", "write"},
+		Tags:          threatstack.NewTagSet(),
+	}
+
+	snap, err := newSnapshot("org", []*rulesetRules{
+		{
+			Ruleset: &threatstack.Ruleset{ID: "rs1", Name: "rs", Description: "desc"},
+			Rules:   []threatstack.Rule{hostRule, fileRule},
+		},
+	})
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	raw, err := json.Marshal(snap)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	restored, err := readSnapshot(bytes.NewReader(raw))
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	if len(restored.Rulesets) != 1 || len(restored.Rulesets[0].Rules) != 2 {
+		test.Fatalf("Unexpected snapshot contents: %s", string(raw))
+	}
+
+	for i, expected := range []threatstack.Rule{hostRule, fileRule} {
+		rule, err := restored.Rulesets[0].Rules[i].rule()
+		if err != nil {
+			test.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(rule, expected) {
+			test.Errorf("Restored rule:\n%#v\n\nExpected:\n%#v", rule, expected)
+		}
+	}
+}
+
+func TestSnapshotVersion(test *testing.T) {
+	_, err := readSnapshot(strings.NewReader(`{"version": 999, "rulesets": []}`))
+	if err == nil || !strings.Contains(err.Error(), "Unsupported snapshot version") {
+		test.Errorf("Expected unsupported version error, got %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/jfcantu/threatstack-golang/threatstack"
+)
+
+// tagCheckSuggestions is the maximum number of similar tags suggested for a
+// tag that doesn't exist.
+const tagCheckSuggestions = 3
+
+// customizeDiffTagCheck checks that every planned include and exclude tag,
+// including default tags, is the tag of at least one agent. It runs after
+// customizeDiffDefaultTags, and does nothing unless the provider's tag_check
+// is "warning" or "error".
+func customizeDiffTagCheck(d *schema.ResourceDiff, meta interface{}) error {
+	m, ok := meta.(*providerMeta)
+	if !ok || m.TagCheck == "" || m.TagCheck == lintSeverityOff {
+		return nil
+	}
+
+	var missing []string
+	for _, block := range []string{"include_tag", "exclude_tag"} {
+		if !d.NewValueKnown(block + "_all") {
+			continue
+		}
+
+		tags := expandTags(d.Get(block + "_all").(*schema.Set).List())
+		if len(tags) == 0 {
+			continue
+		}
+
+		known, err := m.HostTags(d.Get("organization_id").(string))
+		if err != nil {
+			return fmt.Errorf("Error checking that tags exist: %s", err.Error())
+		}
+
+		for _, v := range missingTags(tags, known) {
+			missing = append(missing, fmt.Sprintf("%s %s", block, v))
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	if m.TagCheck == lintSeverityError {
+		return fmt.Errorf("Tags not found on any agent:\n%s", strings.Join(missing, "\n"))
+	}
+	for _, v := range missing {
+		log.Printf("[WARN] Rule %s: tag not found on any agent: %s", d.Get("name"), v)
+	}
+	return nil
+}
+
+// missingTags describes each of tags that isn't in known, along with the
+// most similar known tags.
+func missingTags(tags []*threatstack.Tag, known []*threatstack.Tag) []string {
+	exists := map[string]bool{}
+	for _, v := range known {
+		exists[ruleTagKey(v.Source, v.Key, v.Value)] = true
+	}
+
+	var ret []string
+	for _, v := range tags {
+		if exists[ruleTagKey(v.Source, v.Key, v.Value)] {
+			continue
+		}
+
+		msg := formatTag(v)
+		if similar := similarTags(v, known); len(similar) > 0 {
+			msg += fmt.Sprintf(" (did you mean %s?)", strings.Join(similar, ", "))
+		}
+		ret = append(ret, msg)
+	}
+
+	return ret
+}
+
+// similarTags returns up to tagCheckSuggestions known tags closest to tag, by
+// edit distance. Tags that are too different to be a typo are left out.
+func similarTags(tag *threatstack.Tag, known []*threatstack.Tag) []string {
+	type candidate struct {
+		tag      *threatstack.Tag
+		distance int
+	}
+
+	target := formatTag(tag)
+	var candidates []candidate
+	for _, v := range known {
+		distance := editDistance(target, formatTag(v))
+		if distance <= len(target)/2 {
+			candidates = append(candidates, candidate{v, distance})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].distance < candidates[j].distance
+	})
+
+	var ret []string
+	for i := 0; i < len(candidates) && i < tagCheckSuggestions; i++ {
+		ret = append(ret, formatTag(candidates[i].tag))
+	}
+	return ret
+}
+
+func formatTag(tag *threatstack.Tag) string {
+	return fmt.Sprintf("%s:%s=%s", tag.Source, tag.Key, tag.Value)
+}
+
+// editDistance returns the Levenshtein distance between two strings.
+func editDistance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+
+	prev := make([]int, len(t)+1)
+	cur := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		cur[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+
+			cur[j] = prev[j] + 1
+			if v := cur[j-1] + 1; v < cur[j] {
+				cur[j] = v
+			}
+			if v := prev[j-1] + cost; v < cur[j] {
+				cur[j] = v
+			}
+		}
+		prev, cur = cur, prev
+	}
+
+	return prev[len(t)]
+}
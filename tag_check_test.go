@@ -0,0 +1,78 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/jfcantu/threatstack-golang/threatstack"
+)
+
+var testKnownTags = []*threatstack.Tag{
+	{Source: "ec2", Key: "environment", Value: "production"},
+	{Source: "ec2", Key: "environment", Value: "staging"},
+	{Source: "ec2", Key: "team", Value: "payments"},
+}
+
+func TestMissingTags(test *testing.T) {
+	got := missingTags([]*threatstack.Tag{
+		{Source: "ec2", Key: "environment", Value: "production"},
+		{Source: "ec2", Key: "enviroment", Value: "production"},
+		{Source: "gce", Key: "unrelated", Value: "x"},
+	}, testKnownTags)
+
+	expected := []string{
+		"ec2:enviroment=production (did you mean ec2:environment=production, ec2:environment=staging?)",
+		"gce:unrelated=x",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		test.Errorf("Expected %q, got %q", expected, got)
+	}
+}
+
+func TestEditDistance(test *testing.T) {
+	for _, v := range []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		{"environment", "enviroment", 1},
+	} {
+		if got := editDistance(v.a, v.b); got != v.expected {
+			test.Errorf("editDistance(%q, %q) = %d, expected %d", v.a, v.b, got, v.expected)
+		}
+	}
+}
+
+func TestCustomizeDiffTagCheck(test *testing.T) {
+	raw := testDefaultTagsConfig()
+	raw["include_tag"] = []interface{}{
+		map[string]interface{}{"source": "ec2", "key": "environment", "value": "prodution"},
+	}
+
+	meta := testDefaultTagsMeta()
+	meta.TagCheck = lintSeverityError
+	meta.hostTags = map[string]*hostTagsResult{"": {tags: testKnownTags}}
+
+	_, err := resourceHostRule().Diff(nil, terraform.NewResourceConfigRaw(raw), meta)
+	if err == nil {
+		test.Fatal("Expected an error for tags that don't exist")
+	}
+	for _, expected := range []string{
+		"include_tag ec2:environment=prodution (did you mean ec2:environment=production",
+		"exclude_tag ec2:team=qa",
+		"exclude_tag ec2:environment=sandbox",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			test.Errorf("Expected error containing %q, got %s", expected, err.Error())
+		}
+	}
+
+	meta.TagCheck = lintSeverityWarning
+	if _, err := resourceHostRule().Diff(nil, terraform.NewResourceConfigRaw(raw), meta); err != nil {
+		test.Errorf("Expected no error with tag_check = warning, got %s", err.Error())
+	}
+}
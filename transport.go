@@ -0,0 +1,152 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// transportConfig contains the HTTP transport settings, for networks with a
+// proxy or TLS inspection.
+type transportConfig struct {
+	ProxyURL           string
+	CACertFile         string
+	CACertPEM          string
+	InsecureSkipVerify bool
+	ClientCertFile     string
+	ClientKeyFile      string
+	ClientCertPEM      string
+	ClientKeyPEM       string
+}
+
+// The threatstack client always uses http.DefaultClient, so the transport is
+// shared by every client in the process. installedTransport is the
+// configuration it was set up with.
+var (
+	transportMu        sync.Mutex
+	installedTransport *transportConfig
+)
+
+// envTransportConfig reads the transport settings from the environment, for
+// commands.
+func envTransportConfig() (*transportConfig, error) {
+	config := &transportConfig{
+		ProxyURL:       os.Getenv("THREATSTACK_PROXY_URL"),
+		CACertFile:     os.Getenv("THREATSTACK_CA_CERT_FILE"),
+		ClientCertFile: os.Getenv("THREATSTACK_CLIENT_CERT_FILE"),
+		ClientKeyFile:  os.Getenv("THREATSTACK_CLIENT_KEY_FILE"),
+	}
+
+	if v := os.Getenv("THREATSTACK_INSECURE_SKIP_VERIFY"); v != "" {
+		insecure, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid THREATSTACK_INSECURE_SKIP_VERIFY %q: %s", v, err.Error())
+		}
+		config.InsecureSkipVerify = insecure
+	}
+
+	return config, nil
+}
+
+// Transport builds an HTTP transport from the settings. Anything not set is
+// left as in http.DefaultTransport; in particular, without a proxy URL the
+// HTTPS_PROXY and NO_PROXY environment variables are used.
+func (c *transportConfig) Transport() (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if c.ProxyURL != "" {
+		proxy, err := url.Parse(c.ProxyURL)
+		if err != nil || proxy.Scheme == "" || proxy.Host == "" {
+			return nil, fmt.Errorf("Invalid proxy_url %q, expected e.g. http://proxy.example.com:3128", c.ProxyURL)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	tlsConfig := &tls.Config{}
+
+	if c.CACertFile != "" || c.CACertPEM != "" {
+		pem, name := []byte(c.CACertPEM), "ca_cert_pem"
+		if c.CACertFile != "" {
+			raw, err := ioutil.ReadFile(c.CACertFile)
+			if err != nil {
+				return nil, fmt.Errorf("Error reading ca_cert_file: %s", err.Error())
+			}
+			pem, name = raw, c.CACertFile
+		}
+
+		// The certificates are added to the system's, so that the API is
+		// reachable both with and without TLS inspection.
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			log.Printf("[WARN] Error loading the system certificates, only trusting ca_cert_file or ca_cert_pem: %s", err.Error())
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("No PEM certificates found in %s", name)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	switch {
+	case c.ClientCertFile != "" || c.ClientKeyFile != "":
+		if c.ClientCertFile == "" || c.ClientKeyFile == "" {
+			return nil, fmt.Errorf("client_cert_file and client_key_file must be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(c.ClientCertFile, c.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("Error loading the client certificate: %s", err.Error())
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	case c.ClientCertPEM != "" || c.ClientKeyPEM != "":
+		if c.ClientCertPEM == "" || c.ClientKeyPEM == "" {
+			return nil, fmt.Errorf("client_cert_pem and client_key_pem must be set together")
+		}
+		cert, err := tls.X509KeyPair([]byte(c.ClientCertPEM), []byte(c.ClientKeyPEM))
+		if err != nil {
+			return nil, fmt.Errorf("Error loading the client certificate: %s", err.Error())
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if c.InsecureSkipVerify {
+		log.Println("[WARN] insecure_skip_verify is set: the Threat Stack API's TLS certificate is not verified, and credentials may be sent to an impostor. Prefer ca_cert_file or ca_cert_pem.")
+		tlsConfig.InsecureSkipVerify = true
+	}
+
+	transport.TLSClientConfig = tlsConfig
+
+	return transport, nil
+}
+
+// Install sets up http.DefaultClient with the transport. Since the transport
+// is shared, installing different settings a second time is an error.
+func (c *transportConfig) Install() error {
+	transportMu.Lock()
+	defer transportMu.Unlock()
+
+	if installedTransport != nil {
+		if *installedTransport != *c {
+			return fmt.Errorf("Provider configurations in the same process must use the same proxy and TLS settings")
+		}
+		return nil
+	}
+
+	if *c != (transportConfig{}) {
+		transport, err := c.Transport()
+		if err != nil {
+			return err
+		}
+		http.DefaultClient.Transport = transport
+	}
+
+	installedTransport = c
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func testTLSServer(test *testing.T) (*httptest.Server, string) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "{}")
+	}))
+	test.Cleanup(server.Close)
+
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	return server, string(cert)
+}
+
+func testTransportGet(config *transportConfig, url string) error {
+	transport, err := config.Transport()
+	if err != nil {
+		return err
+	}
+
+	resp, err := (&http.Client{Transport: transport}).Get(url)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func TestTransportConfigCACert(test *testing.T) {
+	server, cert := testTLSServer(test)
+
+	if err := testTransportGet(&transportConfig{}, server.URL); err == nil {
+		test.Error("Expected an error for an untrusted certificate")
+	}
+
+	if err := testTransportGet(&transportConfig{CACertPEM: cert}, server.URL); err != nil {
+		test.Errorf("Expected the certificate to be trusted with ca_cert_pem, got %s", err)
+	}
+
+	if err := testTransportGet(&transportConfig{InsecureSkipVerify: true}, server.URL); err != nil {
+		test.Errorf("Expected the certificate not to be verified with insecure_skip_verify, got %s", err)
+	}
+
+	if _, err := (&transportConfig{CACertPEM: "not a certificate"}).Transport(); err == nil || !strings.Contains(err.Error(), "No PEM certificates") {
+		test.Errorf("Expected an error for an invalid certificate, got %v", err)
+	}
+}
+
+func TestTransportConfigProxy(test *testing.T) {
+	transport, err := (&transportConfig{ProxyURL: "http://proxy.example.com:3128"}).Transport()
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	req, _ := http.NewRequest("GET", "https://api.threatstack.com/v2/rulesets", nil)
+	proxy, err := transport.Proxy(req)
+	if err != nil {
+		test.Fatal(err)
+	}
+	if proxy == nil || proxy.Host != "proxy.example.com:3128" {
+		test.Errorf("Expected requests to go through proxy.example.com:3128, got %v", proxy)
+	}
+
+	if _, err := (&transportConfig{ProxyURL: "proxy.example.com"}).Transport(); err == nil {
+		test.Error("Expected an error for a proxy URL without a scheme")
+	}
+}
+
+func TestTransportConfigClientCertPairs(test *testing.T) {
+	for _, config := range []*transportConfig{
+		{ClientCertFile: "cert.pem"},
+		{ClientKeyPEM: "key"},
+	} {
+		if _, err := config.Transport(); err == nil || !strings.Contains(err.Error(), "must be set together") {
+			test.Errorf("Expected an error for an incomplete client certificate in %+v, got %v", config, err)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/jfcantu/threatstack-golang/threatstack"
+)
+
+// vulnerabilitySeverities are the severities the vulnerabilities API reports.
+var vulnerabilitySeverities = []string{"low", "medium", "high", "critical"}
+
+// vulnerability is a CVE affecting a package, as returned by the
+// vulnerabilities API, which the client library doesn't cover.
+type vulnerability struct {
+	CVENumber       string   `json:"cveNumber"`
+	ReportedPackage string   `json:"reportedPackage"`
+	SystemPackage   string   `json:"systemPackage"`
+	VectorType      string   `json:"vectorType"`
+	Severity        string   `json:"severity"`
+	IsSuppressed    bool     `json:"isSuppressed"`
+	AgentIDs        []string `json:"agentIds"`
+}
+
+type vulnerabilityList struct {
+	CVEs  []*vulnerability `json:"cves"`
+	Token string           `json:"token"`
+}
+
+// vulnerabilityQuery selects vulnerabilities. Empty fields match everything.
+type vulnerabilityQuery struct {
+	CVENumber string
+	Severity  string
+	Package   string
+	AgentID   string
+}
+
+// listVulnerabilities retrieves every vulnerability matching query, following
+// pagination tokens, sorted by CVE and package.
+func listVulnerabilities(client *threatstack.Client, query *vulnerabilityQuery) ([]*vulnerability, error) {
+	params := url.Values{}
+	if query.CVENumber != "" {
+		params.Set("cveNumber", query.CVENumber)
+	}
+	if query.Severity != "" {
+		params.Set("severity", query.Severity)
+	}
+	if query.Package != "" {
+		params.Set("packageName", query.Package)
+	}
+	if query.AgentID != "" {
+		params.Set("agentId", query.AgentID)
+	}
+
+	var ret []*vulnerability
+	for {
+		path := "vulnerabilities"
+		if len(params) > 0 {
+			path += "?" + params.Encode()
+		}
+
+		raw, err := client.GetObject(path, nil)
+		if err != nil {
+			return nil, fmt.Errorf("Error listing vulnerabilities: %s", err.Error())
+		}
+
+		page := new(vulnerabilityList)
+		if err := json.Unmarshal(raw, page); err != nil {
+			return nil, fmt.Errorf("Error parsing vulnerability list: %s", err.Error())
+		}
+
+		ret = append(ret, page.CVEs...)
+		if page.Token == "" {
+			break
+		}
+		params.Set("token", page.Token)
+	}
+
+	sort.SliceStable(ret, func(i, j int) bool {
+		if ret[i].CVENumber != ret[j].CVENumber {
+			return ret[i].CVENumber < ret[j].CVENumber
+		}
+		return ret[i].ReportedPackage < ret[j].ReportedPackage
+	})
+
+	return ret, nil
+}
+
+// vulnerabilitySuppression is an accepted-risk CVE, as used by the
+// vulnerability suppressions API.
+type vulnerabilitySuppression struct {
+	ID        string   `json:"id,omitempty"`
+	CVENumber string   `json:"cveNumber"`
+	Package   string   `json:"packageName,omitempty"`
+	AgentIDs  []string `json:"agentIds,omitempty"`
+	Reason    string   `json:"reason"`
+	ExpiresAt string   `json:"expiresAt,omitempty"`
+	CreatedAt string   `json:"createdAt,omitempty"`
+	CreatedBy string   `json:"createdBy,omitempty"`
+}